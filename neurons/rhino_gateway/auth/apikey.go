@@ -0,0 +1,68 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// APIKeyProvider authenticates the X-Api-Key header against a flat file of
+// one key per line — the same format Rhino Gateway's KeyStore reads,
+// exposed here as a standalone Provider for "apikey://" specs that don't
+// need KeyStore's per-key rate/quota metadata.
+type APIKeyProvider struct {
+	path string
+	mu   sync.RWMutex
+	keys map[string]bool
+}
+
+// NewAPIKeyProvider loads path's key list.
+func NewAPIKeyProvider(path string) (*APIKeyProvider, error) {
+	p := &APIKeyProvider{path: path}
+	if err := p.Reload(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Reload re-reads path, picking up added/removed keys.
+func (p *APIKeyProvider) Reload() error {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return fmt.Errorf("auth: open keys file %s: %w", p.path, err)
+	}
+	defer f.Close()
+
+	keys := make(map[string]bool)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		keys[strings.Fields(line)[0]] = true
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+	return nil
+}
+
+// Validate implements Provider.
+func (p *APIKeyProvider) Validate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := r.Header.Get("X-Api-Key")
+	if key == "" {
+		return "", false
+	}
+	p.mu.RLock()
+	ok := p.keys[key]
+	p.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return key, true
+}
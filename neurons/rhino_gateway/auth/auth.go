@@ -0,0 +1,134 @@
+// Package auth provides pluggable request-authentication backends for
+// Rhino Gateway, selected via a URL-style config string
+// ("apikey://keys.txt", "basicfile:///etc/rhino/htpasswd", "cert://",
+// "static://<key>", "none://") and optionally chained with commas so a
+// request must satisfy more than one, e.g. "cert://,apikey://keys.txt"
+// requires mTLS *and* a valid API key.
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Provider validates an incoming request and, if it's authorized, reports
+// the identity to attribute it to (an API key, a cert CN, a basic-auth
+// username, ...).
+type Provider interface {
+	Validate(w http.ResponseWriter, r *http.Request) (identity string, ok bool)
+}
+
+// Chain requires every Provider in order to accept the request. Its
+// identity is the last non-empty identity reported down the chain, so
+// "cert://,apikey://keys.txt" attributes a request to its API key rather
+// than the less specific certificate CN.
+type Chain []Provider
+
+// Validate implements Provider.
+func (c Chain) Validate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	var identity string
+	for _, p := range c {
+		id, ok := p.Validate(w, r)
+		if !ok {
+			return "", false
+		}
+		if id != "" {
+			identity = id
+		}
+	}
+	return identity, true
+}
+
+// NoneProvider accepts every request unauthenticated — useful for local
+// development or an upstream that already sits behind its own auth.
+type NoneProvider struct{}
+
+// Validate implements Provider.
+func (NoneProvider) Validate(w http.ResponseWriter, r *http.Request) (string, bool) { return "", true }
+
+// StaticProvider accepts requests presenting a single hardcoded API key.
+type StaticProvider struct {
+	key string
+}
+
+// NewStaticProvider creates a StaticProvider accepting key.
+func NewStaticProvider(key string) *StaticProvider { return &StaticProvider{key: key} }
+
+// Validate implements Provider, comparing X-Api-Key in constant time.
+func (p *StaticProvider) Validate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	key := r.Header.Get("X-Api-Key")
+	if key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(p.key)) != 1 {
+		return "", false
+	}
+	return key, true
+}
+
+// New builds a Provider from a comma-separated list of URL-style specs. A
+// single spec returns its provider directly; more than one returns a
+// Chain requiring all of them to accept the request.
+func New(spec string) (Provider, error) {
+	var providers []Provider
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		p, err := newOne(part)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	switch len(providers) {
+	case 0:
+		return nil, fmt.Errorf("auth: empty spec")
+	case 1:
+		return providers[0], nil
+	default:
+		return Chain(providers), nil
+	}
+}
+
+func newOne(spec string) (Provider, error) {
+	scheme, rest, ok := strings.Cut(spec, "://")
+	if !ok {
+		return nil, fmt.Errorf("auth: malformed spec %q (want scheme://...)", spec)
+	}
+
+	switch scheme {
+	case "none":
+		return NoneProvider{}, nil
+	case "static":
+		if rest == "" {
+			return nil, fmt.Errorf("auth: static:// requires a key")
+		}
+		return NewStaticProvider(rest), nil
+	case "apikey":
+		return NewAPIKeyProvider(rest)
+	case "basicfile":
+		return NewHtpasswdProvider(rest)
+	case "cert":
+		return NewCertProvider(rest), nil
+	default:
+		return nil, fmt.Errorf("auth: unknown scheme %q", scheme)
+	}
+}
+
+// FindCertProvider walks p (a single Provider or a Chain) looking for a
+// *CertProvider, so a caller assembling a listener's tls.Config knows
+// whether to wire in ClientCAs and RequireAndVerifyClientCert.
+func FindCertProvider(p Provider) *CertProvider {
+	if chain, ok := p.(Chain); ok {
+		for _, sub := range chain {
+			if cp := FindCertProvider(sub); cp != nil {
+				return cp
+			}
+		}
+		return nil
+	}
+	cp, _ := p.(*CertProvider)
+	return cp
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+)
+
+// CertProvider authenticates via a verified mTLS client certificate,
+// reporting the certificate's Subject CommonName as identity. Validate
+// only checks that the TLS handshake already produced a verified peer
+// certificate — the caller is responsible for wiring ClientCAs() and
+// tls.RequireAndVerifyClientCert into the listener's tls.Config before any
+// request reaches it; see FindCertProvider.
+type CertProvider struct {
+	pool *x509.CertPool
+
+	// Revoked, if set, additionally rejects certificates it reports as
+	// revoked (e.g. against a CRL/OCSP responder the caller polls).
+	Revoked func(cert *x509.Certificate) bool
+}
+
+// NewCertProvider creates a CertProvider. caFile, if non-empty, is loaded
+// immediately as the CA bundle to verify client certs against; leave it
+// empty to reuse a ClientCAs pool the caller configures some other way.
+func NewCertProvider(caFile string) *CertProvider {
+	p := &CertProvider{}
+	if caFile == "" {
+		return p
+	}
+	pool, err := loadCAPool(caFile)
+	if err != nil {
+		return p // caller's tls.Config keeps whatever ClientCAs it already had
+	}
+	p.pool = pool
+	return p
+}
+
+func loadCAPool(path string) (*x509.CertPool, error) {
+	pemBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("auth: read CA bundle %s: %w", path, err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pemBytes) {
+		return nil, fmt.Errorf("auth: no valid certificates in %s", path)
+	}
+	return pool, nil
+}
+
+// ClientCAs returns the CA pool to set on tls.Config.ClientCAs, or nil if
+// this provider was constructed without its own CA bundle.
+func (p *CertProvider) ClientCAs() *x509.CertPool { return p.pool }
+
+// Validate implements Provider.
+func (p *CertProvider) Validate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return "", false
+	}
+	cert := r.TLS.PeerCertificates[0]
+	if p.Revoked != nil && p.Revoked(cert) {
+		return "", false
+	}
+	return cert.Subject.CommonName, true
+}
@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// htpasswdPollInterval is how often HtpasswdProvider checks the backing
+// file's mtime for changes.
+const htpasswdPollInterval = 5 * time.Second
+
+// HtpasswdProvider authenticates HTTP Basic credentials against an Apache
+// htpasswd-style file of "<user>:<bcrypt-hash>" lines, reloading it when
+// its mtime changes — a periodic mtime check in place of the SIGHUP-driven
+// reload the gateway's KeyStore uses, so this provider needs no signal
+// routed to it.
+type HtpasswdProvider struct {
+	path string
+
+	mu    sync.RWMutex
+	users map[string]string // username -> bcrypt hash
+	mtime time.Time
+
+	stop chan struct{}
+}
+
+// NewHtpasswdProvider loads path and starts a background goroutine that
+// re-reads it whenever its mtime changes.
+func NewHtpasswdProvider(path string) (*HtpasswdProvider, error) {
+	p := &HtpasswdProvider{path: path, users: make(map[string]string), stop: make(chan struct{})}
+	if err := p.reload(); err != nil {
+		return nil, err
+	}
+	go p.watch()
+	return p, nil
+}
+
+func (p *HtpasswdProvider) reload() error {
+	f, err := os.Open(p.path)
+	if err != nil {
+		return fmt.Errorf("auth: open htpasswd %s: %w", p.path, err)
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("auth: stat htpasswd %s: %w", p.path, err)
+	}
+
+	users := make(map[string]string)
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		user, hash, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		users[user] = hash
+	}
+
+	p.mu.Lock()
+	p.users = users
+	p.mtime = fi.ModTime()
+	p.mu.Unlock()
+	return nil
+}
+
+// watch polls path's mtime and reloads on change until Close is called.
+func (p *HtpasswdProvider) watch() {
+	ticker := time.NewTicker(htpasswdPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			fi, err := os.Stat(p.path)
+			if err != nil {
+				continue
+			}
+			p.mu.RLock()
+			changed := fi.ModTime().After(p.mtime)
+			p.mu.RUnlock()
+			if changed {
+				p.reload()
+			}
+		}
+	}
+}
+
+// Close stops the background reload goroutine.
+func (p *HtpasswdProvider) Close() { close(p.stop) }
+
+// Validate implements Provider.
+func (p *HtpasswdProvider) Validate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return "", false
+	}
+
+	p.mu.RLock()
+	hash, ok := p.users[user]
+	p.mu.RUnlock()
+	if !ok {
+		return "", false
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(hash), []byte(pass)) != nil {
+		return "", false
+	}
+	return user, true
+}
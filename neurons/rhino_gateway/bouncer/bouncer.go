@@ -0,0 +1,325 @@
+// Package bouncer implements a CrowdSec-style decision bouncer: it maintains
+// a set of banned IPs/CIDRs refreshed from a DecisionSource, and can derive
+// its own local decisions by tailing the gateway's audit log.
+package bouncer
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Decision is a single ban/unban instruction, matching the shape of a
+// CrowdSec LAPI decision.
+type Decision struct {
+	Value    string        // IP or CIDR
+	Duration time.Duration // how long the ban lasts
+	Scope    string        // "Ip" or "Range"
+	Origin   string        // "crowdsec", "local", etc.
+}
+
+// DecisionSource streams ban/unban decisions from an external system.
+type DecisionSource interface {
+	// Stream delivers (new, deleted) decisions on every poll. startup indicates
+	// the first call, matching CrowdSec's `startup=true` semantics.
+	Stream(startup bool) (new []Decision, deleted []Decision, err error)
+}
+
+// entry is a single banned network with its expiry.
+type entry struct {
+	net     *net.IPNet
+	ip      net.IP // set when the decision was a bare IP, nil for CIDR ranges
+	expires time.Time
+	origin  string
+}
+
+// Store holds the current set of banned IPs/CIDRs and can be queried
+// concurrently from the request hot path.
+type Store struct {
+	mu      sync.RWMutex
+	entries map[string]*entry
+}
+
+// NewStore creates an empty decision store.
+func NewStore() *Store {
+	return &Store{entries: make(map[string]*entry)}
+}
+
+// Add inserts or refreshes a decision.
+func (s *Store) Add(d Decision) error {
+	e, err := toEntry(d)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.entries[d.Value] = e
+	s.mu.Unlock()
+	return nil
+}
+
+// Remove deletes a decision by its original value.
+func (s *Store) Remove(value string) {
+	s.mu.Lock()
+	delete(s.entries, value)
+	s.mu.Unlock()
+}
+
+// List returns all active (non-expired) decisions.
+func (s *Store) List() []Decision {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	var out []Decision
+	for value, e := range s.entries {
+		if !e.expires.IsZero() && now.After(e.expires) {
+			continue
+		}
+		out = append(out, Decision{Value: value, Origin: e.origin})
+	}
+	return out
+}
+
+// Banned reports whether addr matches any active decision.
+func (s *Store) Banned(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	now := time.Now()
+	for _, e := range s.entries {
+		if !e.expires.IsZero() && now.After(e.expires) {
+			continue
+		}
+		if e.ip != nil && e.ip.Equal(ip) {
+			return true
+		}
+		if e.net != nil && e.net.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func toEntry(d Decision) (*entry, error) {
+	e := &entry{origin: d.Origin}
+	if d.Duration > 0 {
+		e.expires = time.Now().Add(d.Duration)
+	}
+	if d.Scope == "Range" {
+		_, ipnet, err := net.ParseCIDR(d.Value)
+		if err != nil {
+			return nil, fmt.Errorf("bouncer: invalid range decision %q: %w", d.Value, err)
+		}
+		e.net = ipnet
+		return e, nil
+	}
+	ip := net.ParseIP(d.Value)
+	if ip == nil {
+		return nil, fmt.Errorf("bouncer: invalid ip decision %q", d.Value)
+	}
+	e.ip = ip
+	return e, nil
+}
+
+// Bouncer periodically polls a DecisionSource and applies decisions to a
+// Store. Local decisions derived from the gateway's own logs come from a
+// separate Detector feeding the same Store.
+type Bouncer struct {
+	store    *Store
+	source   DecisionSource
+	interval time.Duration
+	startup  bool
+}
+
+// New creates a Bouncer backed by store, polling source every interval.
+func New(store *Store, source DecisionSource, interval time.Duration) *Bouncer {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	return &Bouncer{store: store, source: source, interval: interval, startup: true}
+}
+
+// Store returns the underlying decision store, so middleware can query it
+// without going through the Bouncer.
+func (b *Bouncer) Store() *Store { return b.store }
+
+// Run polls the DecisionSource until stop is closed.
+func (b *Bouncer) Run(stop <-chan struct{}) {
+	if b.source == nil {
+		return
+	}
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	b.poll()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.poll()
+		}
+	}
+}
+
+func (b *Bouncer) poll() {
+	newDecisions, deleted, err := b.source.Stream(b.startup)
+	b.startup = false
+	if err != nil {
+		return
+	}
+	for _, d := range newDecisions {
+		b.store.Add(d)
+	}
+	for _, d := range deleted {
+		b.store.Remove(d.Value)
+	}
+}
+
+// --- local detection: tail gateway.log and ban noisy IPs ---
+
+// DetectorRule describes a threshold-based local ban rule.
+type DetectorRule struct {
+	// Match selects which audit log lines count towards the threshold,
+	// e.g. func(status int, keyPrefix string) bool { return status == 403 }.
+	Match    func(status int, keyPrefix string) bool
+	Count    int
+	Window   time.Duration
+	BanFor   time.Duration
+	RuleName string
+}
+
+// auditLine is the subset of fields the detector cares about from gateway.log.
+type auditLine struct {
+	ClientIP  string `json:"client_ip"`
+	Status    int    `json:"status"`
+	KeyPrefix string `json:"key_prefix"`
+}
+
+// Detector tails a JSONL audit log and feeds local bans into a Store when a
+// rule's threshold is exceeded within its window.
+type Detector struct {
+	logPath string
+	store   *Store
+	rules   []DetectorRule
+
+	mu     sync.Mutex
+	hits   map[string][]time.Time // ruleName|ip -> hit timestamps within window
+}
+
+// NewDetector creates a Detector that tails logPath and bans into store.
+func NewDetector(logPath string, store *Store, rules []DetectorRule) *Detector {
+	return &Detector{
+		logPath: logPath,
+		store:   store,
+		rules:   rules,
+		hits:    make(map[string][]time.Time),
+	}
+}
+
+// DefaultRules returns a sane starting rule set: ban an IP that racks up
+// too many 403s (bad/missing key) within a short window.
+func DefaultRules() []DetectorRule {
+	return []DetectorRule{
+		{
+			RuleName: "repeated-403",
+			Match:    func(status int, keyPrefix string) bool { return status == 403 },
+			Count:    10,
+			Window:   time.Minute,
+			BanFor:   time.Hour,
+		},
+	}
+}
+
+// Run tails the audit log from its current end and applies rules until stop
+// is closed. It re-opens the file on each poll so log rotation is handled.
+func (d *Detector) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(2 * time.Second)
+	defer ticker.Stop()
+
+	var offset int64
+	if fi, err := os.Stat(d.logPath); err == nil {
+		offset = fi.Size()
+	}
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			offset = d.scan(offset)
+		}
+	}
+}
+
+func (d *Detector) scan(offset int64) int64 {
+	f, err := os.Open(d.logPath)
+	if err != nil {
+		return offset
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return offset
+	}
+	if fi.Size() < offset {
+		offset = 0 // file was truncated/rotated
+	}
+	if _, err := f.Seek(offset, 0); err != nil {
+		return offset
+	}
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var line auditLine
+		if err := json.Unmarshal(sc.Bytes(), &line); err != nil {
+			continue
+		}
+		d.apply(line)
+	}
+	return fi.Size()
+}
+
+func (d *Detector) apply(line auditLine) {
+	now := time.Now()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// gateway.log records r.RemoteAddr ("host:port"); bans are per host.
+	ip := line.ClientIP
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+
+	for _, rule := range d.rules {
+		if !rule.Match(line.Status, line.KeyPrefix) {
+			continue
+		}
+		key := rule.RuleName + "|" + ip
+		hits := append(d.hits[key], now)
+
+		cutoff := now.Add(-rule.Window)
+		var kept []time.Time
+		for _, t := range hits {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+		d.hits[key] = kept
+
+		if len(kept) >= rule.Count {
+			d.store.Add(Decision{
+				Value:    ip,
+				Duration: rule.BanFor,
+				Scope:    "Ip",
+				Origin:   "local:" + rule.RuleName,
+			})
+			delete(d.hits, key)
+		}
+	}
+}
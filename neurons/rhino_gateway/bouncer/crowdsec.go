@@ -0,0 +1,80 @@
+package bouncer
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// CrowdSecSource is a DecisionSource backed by a CrowdSec Local API bouncer
+// stream (`GET /v1/decisions/stream`).
+type CrowdSecSource struct {
+	BaseURL string // e.g. "http://localhost:8080"
+	APIKey  string
+	Client  *http.Client
+}
+
+// NewCrowdSecSource creates a CrowdSec LAPI decision source.
+func NewCrowdSecSource(baseURL, apiKey string) *CrowdSecSource {
+	return &CrowdSecSource{
+		BaseURL: baseURL,
+		APIKey:  apiKey,
+		Client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// decisionsStreamResponse mirrors the CrowdSec LAPI stream payload shape.
+type decisionsStreamResponse struct {
+	New     []crowdsecDecision `json:"new"`
+	Deleted []crowdsecDecision `json:"deleted"`
+}
+
+type crowdsecDecision struct {
+	Value    string `json:"value"`
+	Scope    string `json:"scope"`
+	Duration string `json:"duration"` // e.g. "4h32m"
+	Origin   string `json:"origin"`
+}
+
+// Stream implements DecisionSource.
+func (c *CrowdSecSource) Stream(startup bool) ([]Decision, []Decision, error) {
+	url := fmt.Sprintf("%s/v1/decisions/stream?startup=%s", c.BaseURL, strconv.FormatBool(startup))
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("X-Api-Key", c.APIKey)
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("crowdsec: stream request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("crowdsec: unexpected status %d", resp.StatusCode)
+	}
+
+	var body decisionsStreamResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, nil, fmt.Errorf("crowdsec: decode response: %w", err)
+	}
+
+	return convertDecisions(body.New), convertDecisions(body.Deleted), nil
+}
+
+func convertDecisions(in []crowdsecDecision) []Decision {
+	out := make([]Decision, 0, len(in))
+	for _, d := range in {
+		dur, _ := time.ParseDuration(d.Duration)
+		out = append(out, Decision{
+			Value:    d.Value,
+			Duration: dur,
+			Scope:    d.Scope,
+			Origin:   d.Origin,
+		})
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+// Package certprovider abstracts where Rhino Gateway's TLS certificate
+// comes from: a static file (self-signed or operator-supplied) or an ACME
+// account managed by golang.org/x/crypto/acme/autocert. Both plug into
+// tls.Config.GetCertificate through the same Provider interface, so
+// NewGateway doesn't need to branch on cert source anywhere but its own
+// construction.
+package certprovider
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"io"
+	"net/http"
+	"sync"
+
+	"golang.org/x/crypto/acme/autocert"
+	"golang.org/x/crypto/ocsp"
+)
+
+// Provider resolves the certificate to present for a TLS handshake and,
+// for ACME, the HTTP-01 challenge handler that must sit in front of it.
+type Provider interface {
+	// Certificate implements the tls.Config.GetCertificate signature.
+	Certificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error)
+	// HTTPHandler returns the ACME HTTP-01 challenge handler, or nil for
+	// providers (like FileProvider) that don't need one.
+	HTTPHandler() http.Handler
+}
+
+// FileProvider serves a single static certificate loaded once at startup,
+// e.g. from ensureCert's self-signed pair or an operator-supplied cert/key.
+type FileProvider struct {
+	cert tls.Certificate
+}
+
+// NewFileProvider wraps an already-loaded certificate.
+func NewFileProvider(cert tls.Certificate) *FileProvider {
+	return &FileProvider{cert: cert}
+}
+
+// Certificate implements Provider.
+func (p *FileProvider) Certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return &p.cert, nil
+}
+
+// HTTPHandler implements Provider.
+func (p *FileProvider) HTTPHandler() http.Handler { return nil }
+
+// ACMEProvider manages certificates for a set of hostnames via Let's
+// Encrypt (or any ACME CA), storing them in a pluggable autocert.Cache —
+// the on-disk default, or e.g. RedisCache to share one account's certs
+// across a fleet of gateway pods the same way ratelimit.RedisStore shares
+// rate-limit buckets. It staples the most recently fetched OCSP response
+// onto every certificate it returns.
+type ACMEProvider struct {
+	manager *autocert.Manager
+
+	mu     sync.RWMutex
+	staple []byte
+}
+
+// NewACMEProvider builds an ACMEProvider for domains, registering email
+// with the CA and persisting account/certificate state in cache.
+func NewACMEProvider(domains []string, email string, cache autocert.Cache) *ACMEProvider {
+	return &ACMEProvider{
+		manager: &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(domains...),
+			Cache:      cache,
+			Email:      email,
+		},
+	}
+}
+
+// Certificate implements Provider, attaching the last-fetched OCSP staple
+// (if any) to the ACME-managed certificate.
+func (p *ACMEProvider) Certificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	cert, err := p.manager.GetCertificate(hello)
+	if err != nil {
+		return nil, err
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if len(p.staple) > 0 {
+		cert.OCSPStaple = p.staple
+	}
+	return cert, nil
+}
+
+// HTTPHandler implements Provider, serving ACME HTTP-01 challenges.
+func (p *ACMEProvider) HTTPHandler() http.Handler { return p.manager.HTTPHandler(nil) }
+
+// RefreshStaple fetches a fresh OCSP response for domain's current
+// certificate and stores it for the next Certificate call to attach.
+// Failures are silently ignored — the handshake just goes out unstapled,
+// same as before stapling was added.
+func (p *ACMEProvider) RefreshStaple(domain string) {
+	cert, err := p.manager.GetCertificate(&tls.ClientHelloInfo{ServerName: domain})
+	if err != nil || len(cert.Certificate) < 2 {
+		return
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		return
+	}
+	issuer, err := x509.ParseCertificate(cert.Certificate[1])
+	if err != nil || len(leaf.OCSPServer) == 0 {
+		return
+	}
+
+	req, err := ocsp.CreateRequest(leaf, issuer, nil)
+	if err != nil {
+		return
+	}
+	resp, err := http.Post(leaf.OCSPServer[0], "application/ocsp-request", bytes.NewReader(req))
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.staple = body
+	p.mu.Unlock()
+}
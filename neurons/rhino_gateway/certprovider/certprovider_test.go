@@ -0,0 +1,22 @@
+package certprovider
+
+import (
+	"crypto/tls"
+	"testing"
+)
+
+func TestFileProvider_ReturnsWrappedCert(t *testing.T) {
+	want := tls.Certificate{Certificate: [][]byte{[]byte("leaf")}}
+	p := NewFileProvider(want)
+
+	got, err := p.Certificate(&tls.ClientHelloInfo{ServerName: "example.com"})
+	if err != nil {
+		t.Fatalf("Certificate: %v", err)
+	}
+	if len(got.Certificate) != 1 || string(got.Certificate[0]) != "leaf" {
+		t.Fatalf("expected the wrapped certificate back, got %+v", got)
+	}
+	if p.HTTPHandler() != nil {
+		t.Fatal("expected FileProvider to need no ACME challenge handler")
+	}
+}
@@ -0,0 +1,47 @@
+package certprovider
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// RedisCache implements autocert.Cache on top of the same Redis instance
+// ratelimit.RedisStore uses, so a fleet of gateway pods sharing one ACME
+// account see each other's certificates instead of each pod racing Let's
+// Encrypt to issue its own.
+type RedisCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCache builds a RedisCache namespacing every key under prefix.
+func NewRedisCache(client *redis.Client, prefix string) *RedisCache {
+	return &RedisCache{client: client, prefix: prefix}
+}
+
+// Get implements autocert.Cache.
+func (c *RedisCache) Get(ctx context.Context, name string) ([]byte, error) {
+	data, err := c.client.Get(ctx, c.prefix+name).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, autocert.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Put implements autocert.Cache. autocert keys are never given a TTL — an
+// account key or issued certificate has no expiry autocert itself doesn't
+// already manage — so entries are stored without one, mirroring DirCache.
+func (c *RedisCache) Put(ctx context.Context, name string, data []byte) error {
+	return c.client.Set(ctx, c.prefix+name, data, 0).Err()
+}
+
+// Delete implements autocert.Cache.
+func (c *RedisCache) Delete(ctx context.Context, name string) error {
+	return c.client.Del(ctx, c.prefix+name).Err()
+}
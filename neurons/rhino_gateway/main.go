@@ -3,6 +3,7 @@ package main
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/rand"
 	"crypto/rsa"
@@ -13,6 +14,7 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"io"
 	"log"
 	"math/big"
 	"net"
@@ -21,11 +23,26 @@ import (
 	"net/url"
 	"os"
 	"os/signal"
+	"regexp"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
 	"golang.org/x/time/rate"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/auth"
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/bouncer"
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/certprovider"
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/metrics"
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/ratelimit"
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/router"
+	"github.com/Alartist40/cynapse/neurons/rhino_gateway/validator"
 )
 
 // Config holds runtime configuration
@@ -38,17 +55,96 @@ type Config struct {
 	RateLimit      rate.Limit
 	RateBurst      int
 	RequestTimeout time.Duration
+
+	// RouteLimits overrides RateLimit/RateBurst for requests whose path
+	// matches one of its prefixes, e.g. a tighter limit on "/v1/generate"
+	// than the catch-all default.
+	RouteLimits ratelimit.RouteLimits
+
+	// RateLimiterBackend selects the ratelimit.Store implementation:
+	// "memory" (default) or "redis". Redis lets a fleet of Rhino Gateway
+	// pods share one identity's bucket instead of each pod enforcing its
+	// own independent limit.
+	RateLimiterBackend   string
+	RedisAddr            string
+	RedisKeyPrefix       string
+	MemoryLimiterMaxKeys int
+
+	// Bouncer configuration. CrowdSecURL/CrowdSecKey are optional; when unset
+	// the bouncer still runs standalone off the local Detector.
+	BouncerEnabled  bool
+	CrowdSecURL     string
+	CrowdSecAPIKey  string
+	BouncerInterval time.Duration
+
+	// ACMEDomains, when non-empty, switches the gateway from the self-signed
+	// cert path to automatic certificate management via Let's Encrypt.
+	ACMEDomains  []string
+	ACMEEmail    string
+	ACMECacheDir string
+	// ACMECacheBackend selects the autocert.Cache implementation: "file"
+	// (default, ACMECacheDir on local disk) or "redis", which shares one
+	// ACME account's certificates across a fleet via the same RedisAddr/
+	// RedisKeyPrefix used for rate limiting.
+	ACMECacheBackend string
+	// ACMEHTTPAddr is where the HTTP-01 challenge handler listens (":80" by default).
+	ACMEHTTPAddr string
+
+	// mTLS configuration. ClientAuthMode is one of "none", "request",
+	// "require", or "require_and_verify" (mapped to tls.ClientAuthType).
+	ClientCAFile   string
+	ClientAuthMode string
+	RevocationURL  string // CRL distribution point checked on a ticker
+
+	// AuthSpec selects a pluggable auth.Provider chain (see the auth
+	// package) instead of the legacy X-Api-Key/mTLS logic baked into
+	// Gateway.authenticate, e.g. "cert://,apikey://keys.txt" to require
+	// both. Empty keeps the legacy behavior for backward compatibility.
+	AuthSpec string
+
+	// PromptFields lists the JSONPath-like selectors checked against the
+	// request body by constitutionalGuard, e.g. "$.prompt" for Ollama's
+	// /api/generate and "$.messages[*].content" for OpenAI-compatible
+	// chat completions. Empty disables request-side validation entirely.
+	PromptFields []string
+
+	// MaxRequestsInFlight/MaxMutatingRequestsInFlight bound concurrent
+	// read-only and mutating requests respectively (0 disables the cap),
+	// mirroring kube-apiserver's --max-requests-inflight /
+	// --max-mutating-requests-inflight split. Requests whose method+path
+	// matches LongRunningRequestRE are exempt from both caps and from the
+	// per-request write timeout, since a multi-minute streaming generation
+	// call shouldn't compete with quick reads for the same budget. Empty
+	// LongRunningRequestRE falls back to defaultLongRunningRequestRE.
+	MaxRequestsInFlight         int
+	MaxMutatingRequestsInFlight int
+	LongRunningRequestRE        string
+
+	// RouterFile, if set, loads a router.Table mapping {host, path_prefix}
+	// to distinct upstreams, letting one Rhino instance front several model
+	// backends instead of the single UpstreamURL. Reloaded on SIGHUP
+	// alongside KeysFile. Empty keeps the legacy single-upstream proxy.
+	RouterFile string
+}
+
+// KeyLimits holds the per-key rate and quota overrides parsed from the
+// extended keys.txt format: "<key> <rps> <burst> <daily_tokens>".
+type KeyLimits struct {
+	RPS         rate.Limit
+	Burst       int
+	DailyTokens int64
 }
 
 // KeyStore manages API keys with hot-reload support
 type KeyStore struct {
-	path string
-	keys map[string]bool
-	mu   sync.RWMutex
+	path   string
+	keys   map[string]bool
+	limits map[string]KeyLimits
+	mu     sync.RWMutex
 }
 
 func NewKeyStore(path string) (*KeyStore, error) {
-	ks := &KeyStore{path: path, keys: make(map[string]bool)}
+	ks := &KeyStore{path: path, keys: make(map[string]bool), limits: make(map[string]KeyLimits)}
 	err := ks.Reload()
 	return ks, err
 }
@@ -61,26 +157,61 @@ func (ks *KeyStore) Reload() error {
 	defer f.Close()
 
 	newKeys := make(map[string]bool)
+	newLimits := make(map[string]KeyLimits)
 	sc := bufio.NewScanner(f)
 	for sc.Scan() {
-		key := sc.Text()
-		if key != "" {
-			newKeys[key] = true
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		key := fields[0]
+		newKeys[key] = true
+
+		if len(fields) >= 3 {
+			var rps float64
+			var burst int
+			fmt.Sscanf(fields[1], "%f", &rps)
+			fmt.Sscanf(fields[2], "%d", &burst)
+			limits := KeyLimits{RPS: rate.Limit(rps), Burst: burst}
+			if len(fields) >= 4 {
+				fmt.Sscanf(fields[3], "%d", &limits.DailyTokens)
+			}
+			newLimits[key] = limits
 		}
 	}
 
 	ks.mu.Lock()
 	ks.keys = newKeys
+	ks.limits = newLimits
 	ks.mu.Unlock()
 	return nil
 }
 
+// Limits returns the per-key rate/quota overrides, if the keys.txt line for
+// key included them.
+func (ks *KeyStore) Limits(key string) (KeyLimits, bool) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	l, ok := ks.limits[key]
+	return l, ok
+}
+
 func (ks *KeyStore) Valid(key string) bool {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
 	return ks.keys[key]
 }
 
+// ValidSPKI reports whether fingerprintHex (the hex-encoded SHA-256 of a
+// client certificate's SubjectPublicKeyInfo) was provisioned via a
+// "spki:<hex>" line in keys.txt.
+func (ks *KeyStore) ValidSPKI(fingerprintHex string) bool {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+	return ks.keys["spki:"+fingerprintHex]
+}
+
 func (ks *KeyStore) Count() int {
 	ks.mu.RLock()
 	defer ks.mu.RUnlock()
@@ -89,12 +220,42 @@ func (ks *KeyStore) Count() int {
 
 // Gateway orchestrates the secure proxy
 type Gateway struct {
-	config    *Config
-	proxy     *httputil.ReverseProxy
-	keys      *KeyStore
-	limiter   *rate.Limiter
-	logChan   chan AuditLog
-	tlsConfig *tls.Config
+	config       *Config
+	proxy        *httputil.ReverseProxy
+	keys         *KeyStore
+	limiterStore ratelimit.Store
+	authProvider auth.Provider // nil unless Config.AuthSpec is set; see authenticate
+	validator    *validator.Validator
+	recorder     metrics.Recorder
+	router       *router.Table // nil unless Config.RouterFile is set; see route
+	logChan      chan AuditLog
+
+	readonlyInFlight    *inFlightLimiter
+	mutatingInFlight    *inFlightLimiter
+	longRunningInFlight *inFlightLimiter
+	longRunningRE       *regexp.Regexp
+	tlsConfig           *tls.Config
+	certProvider        certprovider.Provider // self-signed file or ACME; see NewGateway
+
+	bouncer      *bouncer.Bouncer
+	bouncerStop  chan struct{}
+	detectorStop chan struct{}
+
+	keyStateMu sync.Mutex
+	keyState   map[string]*keyState
+
+	revokedMu      sync.RWMutex
+	revokedSerials map[string]bool // hex-encoded serial numbers, from CRL/OCSP
+}
+
+// keyState tracks a key's rolling daily token quota (the RPS/burst limiter
+// itself now lives in the Gateway's ratelimit.Store, shareable across a
+// fleet). It is created lazily on first use, keyed by hashKey(key) so a
+// leaked audit log can't be used to reconstruct active API keys.
+type keyState struct {
+	dailyTokens int64
+	dailyLimit  int64
+	resetAt     time.Time
 }
 
 type AuditLog struct {
@@ -107,9 +268,25 @@ type AuditLog struct {
 	Size       int       `json:"size"`
 	DurationMs int64     `json:"duration_ms"`
 	ClientIP   string    `json:"client_ip"`
+
+	// ClientCertCN/ClientCertFP are populated when the request presented a
+	// verified mTLS client certificate; empty for X-Api-Key auth.
+	ClientCertCN string `json:"client_cert_cn,omitempty"`
+	ClientCertFP string `json:"client_cert_fp,omitempty"`
+
+	// Violations and Article are populated by constitutionalGuard when it
+	// rejects a request or the streaming response scanner flags upstream
+	// output; empty for requests the validator never objected to.
+	Violations []string `json:"violations,omitempty"`
+	Article    string   `json:"article,omitempty"`
 }
 
 func main() {
+	if len(os.Args) > 2 && os.Args[1] == "bouncer" {
+		runBouncerCLI(os.Args[2:])
+		return
+	}
+
 	cfg := loadConfig()
 
 	gw, err := NewGateway(cfg)
@@ -121,17 +298,49 @@ func main() {
 	defer stop()
 
 	server := &http.Server{
-		Addr:         cfg.ListenAddr,
-		Handler:      gw.Handler(),
-		TLSConfig:    gw.tlsConfig,
-		ReadTimeout:  5 * time.Second,
-		WriteTimeout: cfg.RequestTimeout,
-		IdleTimeout:  120 * time.Second,
+		Addr:        cfg.ListenAddr,
+		Handler:     gw.Handler(),
+		TLSConfig:   gw.tlsConfig,
+		ReadTimeout: 5 * time.Second,
+		// No server-wide WriteTimeout: requestTimeout enforces
+		// Config.RequestTimeout per-request via http.TimeoutHandler,
+		// exempting long-running streaming requests entirely.
+		IdleTimeout: 120 * time.Second,
 	}
 
 	go gw.handleReload()
 	go gw.logWorker()
 
+	if gw.bouncer != nil {
+		gw.bouncerStop = make(chan struct{})
+		gw.detectorStop = make(chan struct{})
+		go gw.bouncer.Run(gw.bouncerStop)
+		detector := bouncer.NewDetector("gateway.log", gw.bouncer.Store(), bouncer.DefaultRules())
+		go detector.Run(gw.detectorStop)
+	}
+
+	var httpServer *http.Server
+	if acp, ok := gw.certProvider.(*certprovider.ACMEProvider); ok {
+		httpServer = &http.Server{
+			Addr:    cfg.ACMEHTTPAddr,
+			Handler: acp.HTTPHandler(),
+		}
+		go func() {
+			log.Printf("ACME HTTP-01 challenge listener on %s", cfg.ACMEHTTPAddr)
+			if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("ACME HTTP listener error: %v", err)
+			}
+		}()
+		go func() {
+			ticker := time.NewTicker(1 * time.Hour)
+			defer ticker.Stop()
+			acp.RefreshStaple(cfg.ACMEDomains[0])
+			for range ticker.C {
+				acp.RefreshStaple(cfg.ACMEDomains[0])
+			}
+		}()
+	}
+
 	go func() {
 		log.Printf("🦏 Rhino Gateway v2.0 listening on %s (upstream: %s)", cfg.ListenAddr, cfg.UpstreamURL)
 		if err := server.ListenAndServeTLS("", ""); err != nil && err != http.ErrServerClosed {
@@ -148,6 +357,9 @@ func main() {
 	if err := server.Shutdown(shutdownCtx); err != nil {
 		log.Printf("Shutdown error: %v", err)
 	}
+	if httpServer != nil {
+		httpServer.Shutdown(shutdownCtx)
+	}
 	gw.Shutdown()
 }
 
@@ -162,47 +374,576 @@ func NewGateway(cfg *Config) (*Gateway, error) {
 		log.Printf("Warning: Failed to load keys from %s: %v", cfg.KeysFile, err)
 	}
 
-	if err := ensureCert(cfg.CertFile, cfg.KeyFile); err != nil {
-		return nil, err
+	proxy := httputil.NewSingleHostReverseProxy(upstream)
+	proxy.FlushInterval = -1 // stream responses chunk-by-chunk (SSE/ndjson from Ollama)
+
+	recorder := metrics.New()
+	proxy.Transport = &metrics.InstrumentedTransport{Next: http.DefaultTransport, Recorder: recorder}
+
+	gw := &Gateway{
+		config:       cfg,
+		proxy:        proxy,
+		keys:         keys,
+		limiterStore: newLimiterStore(cfg),
+		validator:    validator.New(),
+		recorder:     recorder,
+		logChan:      make(chan AuditLog, 100),
+		keyState:     make(map[string]*keyState),
+	}
+	proxy.ModifyResponse = gw.streamingResponseModifier
+	if keys != nil {
+		recorder.SetKeyStoreSize(keys.Count())
 	}
 
-	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if cfg.RouterFile != "" {
+		rt := router.New(&metrics.InstrumentedTransport{Next: http.DefaultTransport, Recorder: recorder})
+		if err := rt.Load(cfg.RouterFile); err != nil {
+			return nil, fmt.Errorf("router: %w", err)
+		}
+		gw.router = rt
+	}
+
+	if cfg.AuthSpec != "" {
+		provider, err := auth.New(cfg.AuthSpec)
+		if err != nil {
+			return nil, fmt.Errorf("auth: %w", err)
+		}
+		gw.authProvider = provider
+	}
+
+	longRunningPattern := cfg.LongRunningRequestRE
+	if longRunningPattern == "" {
+		longRunningPattern = defaultLongRunningRequestRE
+	}
+	longRunningRE, err := regexp.Compile(longRunningPattern)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load certificates: %w", err)
+		return nil, fmt.Errorf("invalid LongRunningRequestRE %q: %w", longRunningPattern, err)
 	}
+	gw.longRunningRE = longRunningRE
+	gw.readonlyInFlight = newInFlightLimiter(cfg.MaxRequestsInFlight)
+	gw.mutatingInFlight = newInFlightLimiter(cfg.MaxMutatingRequestsInFlight)
+	gw.longRunningInFlight = newInFlightLimiter(0)
+
+	if len(cfg.ACMEDomains) > 0 {
+		if cfg.ACMEHTTPAddr == "" {
+			cfg.ACMEHTTPAddr = ":80"
+		}
+
+		var cache autocert.Cache
+		if cfg.ACMECacheBackend == "redis" {
+			prefix := cfg.RedisKeyPrefix
+			if prefix == "" {
+				prefix = "cynapse:ratelimit:"
+			}
+			cache = certprovider.NewRedisCache(redis.NewClient(&redis.Options{Addr: cfg.RedisAddr}), prefix+"acme:")
+		} else {
+			if cfg.ACMECacheDir == "" {
+				cfg.ACMECacheDir = "acme-cache"
+			}
+			cache = autocert.DirCache(cfg.ACMECacheDir)
+		}
+
+		acp := certprovider.NewACMEProvider(cfg.ACMEDomains, cfg.ACMEEmail, cache)
+		gw.certProvider = acp
+		gw.tlsConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: acp.Certificate,
+			NextProtos:     []string{"h2", "http/1.1", acme.ALPNProto},
+		}
+	} else {
+		if err := ensureCert(cfg.CertFile, cfg.KeyFile); err != nil {
+			return nil, err
+		}
+
+		cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load certificates: %w", err)
+		}
 
-	tlsConfig := &tls.Config{
-		Certificates: []tls.Certificate{cert},
-		MinVersion:   tls.VersionTLS12,
+		gw.certProvider = certprovider.NewFileProvider(cert)
+		gw.tlsConfig = &tls.Config{
+			GetCertificate: gw.certProvider.Certificate,
+			MinVersion:     tls.VersionTLS12,
+		}
 	}
 
-	proxy := httputil.NewSingleHostReverseProxy(upstream)
+	if cfg.BouncerEnabled {
+		gw.setupBouncer()
+	}
 
-	gw := &Gateway{
-		config:    cfg,
-		proxy:     proxy,
-		keys:      keys,
-		limiter:   rate.NewLimiter(cfg.RateLimit, cfg.RateBurst),
-		logChan:   make(chan AuditLog, 100),
-		tlsConfig: tlsConfig,
+	if cp := auth.FindCertProvider(gw.authProvider); cp != nil {
+		// AuthSpec opted into mTLS itself, so it takes over CA/ClientAuth
+		// wiring from the legacy ClientCAFile path below. A cert:// spec
+		// with no CA path of its own (cp.ClientCAs() == nil) still needs a
+		// pool from somewhere, or RequireAndVerifyClientCert below would
+		// hand tls.Config a nil ClientCAs, which makes Go's TLS stack fall
+		// back to the system root pool and accept any publicly-trusted
+		// cert as a client identity. Fall back to the legacy ClientCAFile
+		// so operators migrating to AuthSpec don't have to move the CA
+		// bundle in the same step, but fail closed if neither has one.
+		pool := cp.ClientCAs()
+		if pool == nil && cfg.ClientCAFile != "" {
+			p, err := loadClientCAPool(cfg.ClientCAFile)
+			if err != nil {
+				return nil, err
+			}
+			pool = p
+		}
+		if pool == nil {
+			return nil, fmt.Errorf("auth: cert:// provider has no client CA bundle and ClientCAFile is not set")
+		}
+		gw.tlsConfig.ClientCAs = pool
+		gw.tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	} else if cfg.ClientCAFile != "" {
+		if err := gw.setupMTLS(); err != nil {
+			return nil, err
+		}
 	}
 
 	return gw, nil
 }
 
+// newLimiterStore builds the ratelimit.Store backing cfg.RateLimiterBackend.
+// "redis" shares buckets across a fleet of gateway pods; anything else
+// (including the empty default) keeps limits in-process, bounded by an LRU
+// so a flood of distinct unauthenticated client IPs can't grow it forever.
+func newLimiterStore(cfg *Config) ratelimit.Store {
+	if cfg.RateLimiterBackend == "redis" {
+		client := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+		prefix := cfg.RedisKeyPrefix
+		if prefix == "" {
+			prefix = "cynapse:ratelimit:"
+		}
+		return ratelimit.NewRedisStore(client, prefix)
+	}
+	return ratelimit.NewMemoryStore(cfg.MemoryLimiterMaxKeys)
+}
+
+// defaultLongRunningRequestRE matches Ollama's streaming generation
+// endpoints when Config.LongRunningRequestRE is unset.
+const defaultLongRunningRequestRE = `^(POST) /api/(generate|chat)$`
+
+// inFlightLimiter bounds concurrent requests in one scope (readonly,
+// mutating, or long-running) with a channel-backed semaphore, while
+// tracking an atomic counter so /metrics can report how many are in
+// flight without contending with Acquire/Release. max<=0 means unbounded
+// — Acquire always succeeds — but the scope is still counted.
+type inFlightLimiter struct {
+	sem     chan struct{}
+	current int64
+}
+
+func newInFlightLimiter(max int) *inFlightLimiter {
+	if max <= 0 {
+		return &inFlightLimiter{}
+	}
+	return &inFlightLimiter{sem: make(chan struct{}, max)}
+}
+
+// Acquire reports whether the request may proceed.
+func (l *inFlightLimiter) Acquire() bool {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		default:
+			return false
+		}
+	}
+	atomic.AddInt64(&l.current, 1)
+	return true
+}
+
+// Release must be called exactly once for every successful Acquire.
+func (l *inFlightLimiter) Release() {
+	atomic.AddInt64(&l.current, -1)
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+func (l *inFlightLimiter) Current() int64 {
+	return atomic.LoadInt64(&l.current)
+}
+
+// inFlightLimit bounds concurrent read-only and mutating requests
+// separately (mirroring kube-apiserver's max-requests-inflight split),
+// exempting anything longRunningRE matches so a multi-minute streaming
+// /api/generate call neither counts against nor waits behind the same
+// budget as a quick /api/tags poll.
+func (g *Gateway) inFlightLimit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		scope := "readonly"
+		limiter := g.readonlyInFlight
+		longRunning := g.longRunningRE.MatchString(r.Method + " " + r.URL.Path)
+		if !longRunning && g.router != nil {
+			if rt, _, ok := g.router.Match(r.Host, r.URL.Path); ok {
+				longRunning = rt.LongRunning
+			}
+		}
+		switch {
+		case longRunning:
+			scope, limiter = "longrunning", g.longRunningInFlight
+		case isMutatingMethod(r.Method):
+			scope, limiter = "mutating", g.mutatingInFlight
+		}
+
+		if !limiter.Acquire() {
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many requests in flight", http.StatusTooManyRequests)
+			return
+		}
+		g.recorder.SetInFlight(scope, limiter.Current())
+		defer func() {
+			limiter.Release()
+			g.recorder.SetInFlight(scope, limiter.Current())
+		}()
+
+		if next != nil {
+			next(w, r)
+		}
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// routeMatch carries the outcome of route into requestTimeout and
+// proxyRequest via the request context.
+type routeMatch struct {
+	route router.Route
+	proxy *httputil.ReverseProxy
+}
+
+// route picks which backend a request is forwarded to when Config.RouterFile
+// is set, letting one Rhino instance front several model backends with
+// distinct auth/timeout policies instead of the single legacy UpstreamURL.
+// With no router configured it's a no-op and proxyRequest falls back to the
+// single-upstream gw.proxy.
+func (g *Gateway) route(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.router == nil {
+			if next != nil {
+				next(w, r)
+			}
+			return
+		}
+
+		rt, proxy, ok := g.router.Match(r.Host, r.URL.Path)
+		if !ok {
+			http.Error(w, "no route for request", http.StatusNotFound)
+			return
+		}
+		if !rt.AllowsMethod(r.Method) {
+			http.Error(w, "method not allowed for this route", http.StatusMethodNotAllowed)
+			return
+		}
+		if len(rt.RequiredKeys) > 0 {
+			key, _ := r.Context().Value("api_key").(string)
+			if !containsString(rt.RequiredKeys, key) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		if rt.RewritePrefix != "" {
+			r.URL.Path = rt.RewritePrefix + strings.TrimPrefix(r.URL.Path, rt.PathPrefix)
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), "route_match", &routeMatch{route: rt, proxy: proxy}))
+		if next != nil {
+			next(w, r)
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// requestTimeout wraps non-long-running requests in an http.TimeoutHandler
+// bounded by Config.RequestTimeout (or the matched route's Timeout
+// override), since the server's own WriteTimeout is left unset to let
+// long-running streaming responses run indefinitely.
+func (g *Gateway) requestTimeout(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		longRunning := g.longRunningRE.MatchString(r.Method + " " + r.URL.Path)
+		timeout := g.config.RequestTimeout
+		if m, ok := r.Context().Value("route_match").(*routeMatch); ok {
+			longRunning = longRunning || m.route.LongRunning
+			if m.route.Timeout > 0 {
+				timeout = m.route.Timeout
+			}
+		}
+
+		if longRunning || next == nil {
+			if next != nil {
+				next(w, r)
+			}
+			return
+		}
+		http.TimeoutHandler(http.HandlerFunc(next), timeout, "request timed out").ServeHTTP(w, r)
+	}
+}
+
+func (g *Gateway) handleMetrics(w http.ResponseWriter, r *http.Request) {
+	g.recorder.Handler().ServeHTTP(w, r)
+}
+
+// loadClientCAPool reads and parses the PEM client CA bundle at path.
+func loadClientCAPool(path string) (*x509.CertPool, error) {
+	caPEM, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no valid certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+// setupMTLS loads the client CA bundle and configures the TLS listener to
+// request/require client certificates per Config.ClientAuthMode. X-Api-Key
+// keeps working, so operators can roll mTLS out gradually per key.
+func (g *Gateway) setupMTLS() error {
+	pool, err := loadClientCAPool(g.config.ClientCAFile)
+	if err != nil {
+		return err
+	}
+
+	g.tlsConfig.ClientCAs = pool
+	switch g.config.ClientAuthMode {
+	case "require":
+		g.tlsConfig.ClientAuth = tls.RequireAnyClientCert
+	case "require_and_verify":
+		g.tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	case "request":
+		g.tlsConfig.ClientAuth = tls.RequestClientCert
+	default:
+		g.tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+
+	g.revokedSerials = make(map[string]bool)
+	if g.config.RevocationURL != "" {
+		go g.revocationWorker()
+	}
+
+	return nil
+}
+
+// revocationWorker periodically fetches the configured CRL and refreshes
+// the set of revoked certificate serials checked on every handshake.
+func (g *Gateway) revocationWorker() {
+	ticker := time.NewTicker(1 * time.Hour)
+	defer ticker.Stop()
+	g.refreshCRL()
+	for range ticker.C {
+		g.refreshCRL()
+	}
+}
+
+func (g *Gateway) refreshCRL() {
+	resp, err := http.Get(g.config.RevocationURL)
+	if err != nil {
+		log.Printf("CRL fetch failed: %v", err)
+		return
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return
+	}
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		log.Printf("CRL parse failed: %v", err)
+		return
+	}
+
+	revoked := make(map[string]bool, len(crl.RevokedCertificateEntries))
+	for _, rc := range crl.RevokedCertificateEntries {
+		revoked[rc.SerialNumber.Text(16)] = true
+	}
+
+	g.revokedMu.Lock()
+	g.revokedSerials = revoked
+	g.revokedMu.Unlock()
+}
+
+// certRevoked reports whether cert's serial appears in the last-fetched CRL.
+func (g *Gateway) certRevoked(cert *x509.Certificate) bool {
+	g.revokedMu.RLock()
+	defer g.revokedMu.RUnlock()
+	return g.revokedSerials[cert.SerialNumber.Text(16)]
+}
+
+// spkiFingerprint returns the hex-encoded SHA-256 of a certificate's
+// SubjectPublicKeyInfo, used as the mTLS identity looked up in KeyStore.
+func spkiFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return hex.EncodeToString(sum[:])
+}
+
+// setupBouncer wires a decision store fed by an optional CrowdSec LAPI
+// stream and a local Detector that tails gateway.log for noisy clients.
+// Both feed the same Store, so the bouncer middleware works even without
+// an external LAPI.
+func (g *Gateway) setupBouncer() {
+	store := bouncer.NewStore()
+
+	var source bouncer.DecisionSource
+	if g.config.CrowdSecURL != "" {
+		source = bouncer.NewCrowdSecSource(g.config.CrowdSecURL, g.config.CrowdSecAPIKey)
+	}
+	g.bouncer = bouncer.New(store, source, g.config.BouncerInterval)
+}
+
 func (g *Gateway) Handler() http.Handler {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/health", g.handleHealth)
+	mux.HandleFunc("/metrics", g.handleMetrics)
+	if g.bouncer != nil {
+		// Bans and unbans clients, so it needs the same authentication as
+		// every proxied request — registering it unauthenticated alongside
+		// /health would let anyone un-ban themselves or ban a victim IP.
+		mux.HandleFunc("/metrics/bouncer", g.authenticate(g.handleBouncerMetrics))
+	}
 	mux.HandleFunc("/", g.chain(
 		g.securityHeaders,
+		g.bounce,
+		g.inFlightLimit,
 		g.rateLimit,
 		g.authenticate,
+		g.constitutionalGuard,
 		g.auditLog,
+		g.route,
+		g.requestTimeout,
 		g.proxyRequest,
 	))
 	return mux
 }
 
+// bounce rejects requests from clients with an active bouncer decision,
+// before rate limiting or auth ever run.
+func (g *Gateway) bounce(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.bouncer != nil {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			if g.bouncer.Store().Banned(host) {
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+		}
+		if next != nil {
+			next(w, r)
+		}
+	}
+}
+
+func (g *Gateway) handleBouncerMetrics(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		decisions := g.bouncer.Store().List()
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"active_decisions": len(decisions),
+			"decisions":        decisions,
+		})
+	case http.MethodPost:
+		var d bouncer.Decision
+		if err := json.NewDecoder(r.Body).Decode(&d); err != nil {
+			http.Error(w, "invalid decision", http.StatusBadRequest)
+			return
+		}
+		if d.Scope == "" {
+			d.Scope = "Ip"
+		}
+		if d.Origin == "" {
+			d.Origin = "cli"
+		}
+		if err := g.bouncer.Store().Add(d); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodDelete:
+		value := r.URL.Query().Get("value")
+		g.bouncer.Store().Remove(value)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// runBouncerCLI implements the `cynapse bouncer list|add|del` verbs by
+// talking to a running gateway's /metrics/bouncer admin endpoint.
+func runBouncerCLI(args []string) {
+	adminURL := os.Getenv("RHINO_ADMIN_URL")
+	if adminURL == "" {
+		adminURL = "https://localhost:8443/metrics/bouncer"
+	}
+	client := &http.Client{Timeout: 10 * time.Second}
+
+	switch args[0] {
+	case "list":
+		resp, err := client.Get(adminURL)
+		if err != nil {
+			log.Fatalf("bouncer list: %v", err)
+		}
+		defer resp.Body.Close()
+		io.Copy(os.Stdout, resp.Body)
+
+	case "add":
+		if len(args) < 2 {
+			log.Fatal("usage: cynapse bouncer add <ip-or-cidr> [duration]")
+		}
+		d := bouncer.Decision{Value: args[1], Scope: "Ip", Origin: "cli"}
+		if strings.Contains(args[1], "/") {
+			d.Scope = "Range"
+		}
+		if len(args) > 2 {
+			dur, err := time.ParseDuration(args[2])
+			if err != nil {
+				log.Fatalf("bouncer add: invalid duration: %v", err)
+			}
+			d.Duration = dur
+		}
+		body, _ := json.Marshal(d)
+		resp, err := client.Post(adminURL, "application/json", bytes.NewReader(body))
+		if err != nil {
+			log.Fatalf("bouncer add: %v", err)
+		}
+		resp.Body.Close()
+
+	case "del":
+		if len(args) < 2 {
+			log.Fatal("usage: cynapse bouncer del <ip-or-cidr>")
+		}
+		req, _ := http.NewRequest(http.MethodDelete, adminURL+"?value="+args[1], nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			log.Fatalf("bouncer del: %v", err)
+		}
+		resp.Body.Close()
+
+	default:
+		log.Fatalf("unknown bouncer verb: %s", args[0])
+	}
+}
+
 func (g *Gateway) chain(handlers ...func(http.HandlerFunc) http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		final := handlers[len(handlers)-1](nil)
@@ -227,18 +968,173 @@ func (g *Gateway) securityHeaders(next http.HandlerFunc) http.HandlerFunc {
 
 func (g *Gateway) rateLimit(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if !g.limiter.Allow() {
+		key := r.Header.Get("X-Api-Key")
+		if key == "" {
+			host, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				host = r.RemoteAddr
+			}
+			key = "ip:" + host // unauthenticated paths are limited per client IP
+		}
+		ks := g.getKeyState(key)
+
+		// dailyTokens and resetAt are also mutated from recordTokenUsage on
+		// the response-streaming goroutine, so every read needs the same
+		// lock as the resets below, not just the resets themselves.
+		g.keyStateMu.Lock()
+		if ks.dailyLimit > 0 && time.Now().After(ks.resetAt) {
+			ks.dailyTokens = 0
+			ks.resetAt = nextMidnightUTC()
+		}
+		dailyTokens, resetAt := ks.dailyTokens, ks.resetAt
+		g.keyStateMu.Unlock()
+
+		if ks.dailyLimit > 0 && dailyTokens >= ks.dailyLimit {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(time.Until(resetAt).Seconds())))
+			g.recorder.IncRateLimitRejection()
+			http.Error(w, "Daily token quota exceeded", http.StatusTooManyRequests)
+			return
+		}
+
+		rps, burst := g.rpsLimits(key, r.URL.Path)
+		decision, err := g.limiterStore.Allow(r.Context(), hashKey(key), rps, burst)
+		if err != nil {
+			log.Printf("rate limiter: %v", err)
+			if next != nil {
+				next(w, r)
+			}
+			return
+		}
+
+		w.Header().Set("X-RateLimit-Limit", fmt.Sprintf("%.0f", rps))
+		if !decision.Allowed {
+			w.Header().Set("Retry-After", fmt.Sprintf("%d", int(decision.RetryAfter.Seconds())+1))
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			g.recorder.IncRateLimitRejection()
 			http.Error(w, "Rate limit exceeded", http.StatusTooManyRequests)
 			return
 		}
+		w.Header().Set("X-RateLimit-Remaining", fmt.Sprintf("%.0f", decision.Remaining))
 		if next != nil {
 			next(w, r)
 		}
 	}
 }
 
+// rpsLimits resolves the rate/burst to apply to key for a request against
+// path: a per-key override from KeyStore wins, then the longest-matching
+// RouteLimits prefix, then the gateway-wide default.
+func (g *Gateway) rpsLimits(key, path string) (rps float64, burst int) {
+	rps, burst = float64(g.config.RateLimit), g.config.RateBurst
+	if route, ok := g.config.RouteLimits.Match(path); ok {
+		rps, burst = route.RPS, route.Burst
+	}
+	if g.keys != nil {
+		if limits, ok := g.keys.Limits(key); ok {
+			rps, burst = float64(limits.RPS), limits.Burst
+		}
+	}
+	return rps, burst
+}
+
+// getKeyState returns (creating if necessary) the per-key daily-quota
+// state, keyed by hashKey(key) so raw keys never sit in the map.
+func (g *Gateway) getKeyState(key string) *keyState {
+	id := hashKey(key)
+
+	g.keyStateMu.Lock()
+	defer g.keyStateMu.Unlock()
+
+	ks, ok := g.keyState[id]
+	if ok {
+		return ks
+	}
+
+	var daily int64
+	if g.keys != nil {
+		if limits, ok := g.keys.Limits(key); ok {
+			daily = limits.DailyTokens
+		}
+	}
+
+	ks = &keyState{
+		dailyLimit: daily,
+		resetAt:    nextMidnightUTC(),
+	}
+	g.keyState[id] = ks
+	return ks
+}
+
+func nextMidnightUTC() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC)
+}
+
+// recordTokenUsage adds tokens consumed by key to its rolling daily counter,
+// parsed from the upstream's Ollama-style eval_count/prompt_eval_count.
+func (g *Gateway) recordTokenUsage(key string, tokens int64) {
+	if tokens <= 0 {
+		return
+	}
+	id := hashKey(key)
+	g.keyStateMu.Lock()
+	defer g.keyStateMu.Unlock()
+	if ks, ok := g.keyState[id]; ok {
+		ks.dailyTokens += tokens
+	}
+}
+
+// authenticate accepts either a verified mTLS client certificate or the
+// legacy X-Api-Key header, so operators can roll mTLS out gradually per key.
 func (g *Gateway) authenticate(next http.HandlerFunc) http.HandlerFunc {
+	if g.authProvider != nil {
+		return g.authenticatePluggable(next)
+	}
 	return func(w http.ResponseWriter, r *http.Request) {
+		var certCN, certFP string
+
+		if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+			cert := r.TLS.PeerCertificates[0]
+			certFP = spkiFingerprint(cert)
+			certCN = cert.Subject.CommonName
+
+			if g.certRevoked(cert) {
+				g.logChan <- AuditLog{
+					Timestamp:    time.Now(),
+					Path:         r.URL.Path,
+					Method:       r.Method,
+					Status:       403,
+					ClientIP:     r.RemoteAddr,
+					ClientCertCN: certCN,
+					ClientCertFP: certFP,
+				}
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			if g.keys != nil && !g.keys.ValidSPKI(certFP) {
+				g.logChan <- AuditLog{
+					Timestamp:    time.Now(),
+					Path:         r.URL.Path,
+					Method:       r.Method,
+					Status:       403,
+					ClientIP:     r.RemoteAddr,
+					ClientCertCN: certCN,
+					ClientCertFP: certFP,
+				}
+				http.Error(w, "Forbidden", http.StatusForbidden)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), "api_key", "spki:"+certFP)
+			ctx = context.WithValue(ctx, "cert_cn", certCN)
+			ctx = context.WithValue(ctx, "cert_fp", certFP)
+			if next != nil {
+				next(w, r.WithContext(ctx))
+			}
+			return
+		}
+
 		key := r.Header.Get("X-Api-Key")
 		if g.keys != nil && !g.keys.Valid(key) {
 			g.logChan <- AuditLog{
@@ -260,6 +1156,163 @@ func (g *Gateway) authenticate(next http.HandlerFunc) http.HandlerFunc {
 	}
 }
 
+// authenticatePluggable validates the request against g.authProvider, the
+// Auth backend selected by Config.AuthSpec, in place of the legacy
+// X-Api-Key/mTLS logic above.
+func (g *Gateway) authenticatePluggable(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		identity, ok := g.authProvider.Validate(w, r)
+		if !ok {
+			g.logChan <- AuditLog{
+				Timestamp: time.Now(),
+				Path:      r.URL.Path,
+				Method:    r.Method,
+				Status:    403,
+				ClientIP:  r.RemoteAddr,
+			}
+			http.Error(w, "Forbidden", http.StatusForbidden)
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), "api_key", identity)
+		if next != nil {
+			next(w, r.WithContext(ctx))
+		}
+	}
+}
+
+// constitutionalGuard buffers the request body and runs Config.PromptFields
+// through the constitutional Validator before the request reaches the
+// upstream, rejecting it with 422 on the first violation. The response side
+// of the same validation (scanning streamed upstream output) is wired into
+// streamingResponseModifier below, since a single ModifyResponse hook is
+// all a ReverseProxy allows. PromptFields empty disables both.
+func (g *Gateway) constitutionalGuard(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if len(g.config.PromptFields) == 0 || r.Body == nil {
+			if next != nil {
+				next(w, r)
+			}
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		r.Body.Close()
+		if err != nil {
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		for _, prompt := range extractPromptFields(body, g.config.PromptFields) {
+			result := g.validator.ValidateInput(prompt)
+			if result.Passed {
+				continue
+			}
+
+			key, _ := r.Context().Value("api_key").(string)
+			article := articleOf(result.Violations)
+			g.logChan <- AuditLog{
+				Timestamp:  time.Now(),
+				Path:       r.URL.Path,
+				Method:     r.Method,
+				Status:     http.StatusUnprocessableEntity,
+				ClientIP:   r.RemoteAddr,
+				KeyPrefix:  maskKey(key),
+				KeyHash:    hashKey(key),
+				Violations: result.Violations,
+				Article:    article,
+			}
+			g.recorder.ObserveViolation(article)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"error":      "constitutional violation",
+				"violations": result.Violations,
+			})
+			return
+		}
+
+		if next != nil {
+			next(w, r)
+		}
+	}
+}
+
+// extractPromptFields evaluates each of selectors against the JSON request
+// body, returning every string value they match.
+func extractPromptFields(body []byte, selectors []string) []string {
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil
+	}
+
+	var out []string
+	for _, sel := range selectors {
+		out = append(out, evalJSONPath(doc, sel)...)
+	}
+	return out
+}
+
+// evalJSONPath supports the small subset of JSONPath Config.PromptFields
+// needs: a dotted path of field names, optionally with a "[*]" suffix on a
+// segment to fan out over an array, e.g. "$.prompt" or
+// "$.messages[*].content".
+func evalJSONPath(doc interface{}, sel string) []string {
+	sel = strings.TrimPrefix(sel, "$.")
+	cur := []interface{}{doc}
+	for _, part := range strings.Split(sel, ".") {
+		name := part
+		wildcard := strings.HasSuffix(part, "[*]")
+		if wildcard {
+			name = strings.TrimSuffix(part, "[*]")
+		}
+
+		var next []interface{}
+		for _, v := range cur {
+			m, ok := v.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			child, ok := m[name]
+			if !ok {
+				continue
+			}
+			if wildcard {
+				if arr, ok := child.([]interface{}); ok {
+					next = append(next, arr...)
+				}
+			} else {
+				next = append(next, child)
+			}
+		}
+		cur = next
+	}
+
+	var out []string
+	for _, v := range cur {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// articlePattern extracts the constitutional article a Validator violation
+// message names, e.g. "Article II" out of "Output violates Article II
+// (Purity)".
+var articlePattern = regexp.MustCompile(`Article [IVXLC]+`)
+
+func articleOf(violations []string) string {
+	for _, v := range violations {
+		if m := articlePattern.FindString(v); m != "" {
+			return m
+		}
+	}
+	return ""
+}
+
 func (g *Gateway) auditLog(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
@@ -268,24 +1321,262 @@ func (g *Gateway) auditLog(next http.HandlerFunc) http.HandlerFunc {
 		if next != nil {
 			next(rec, r)
 		}
+		duration := time.Since(start)
 
 		key, _ := r.Context().Value("api_key").(string)
-		g.logChan <- AuditLog{
-			Timestamp:  time.Now(),
-			KeyPrefix:  maskKey(key),
-			KeyHash:    hashKey(key),
-			Path:       r.URL.Path,
-			Method:     r.Method,
-			Status:     rec.status,
-			Size:       rec.size,
-			DurationMs: time.Since(start).Milliseconds(),
-			ClientIP:   r.RemoteAddr,
+		certCN, _ := r.Context().Value("cert_cn").(string)
+		certFP, _ := r.Context().Value("cert_fp").(string)
+
+		entry := AuditLog{
+			Timestamp:    time.Now(),
+			Path:         r.URL.Path,
+			Method:       r.Method,
+			Status:       rec.status,
+			Size:         rec.size,
+			DurationMs:   duration.Milliseconds(),
+			ClientIP:     r.RemoteAddr,
+			ClientCertCN: certCN,
+			ClientCertFP: certFP,
+		}
+		if certFP == "" {
+			entry.KeyPrefix = maskKey(key)
+			entry.KeyHash = hashKey(key)
+		}
+		g.logChan <- entry
+		g.recorder.ObserveRequest(r.Method, r.URL.Path, rec.status, maskKey(key), duration)
+	}
+}
+
+// proxyRequest is the terminal link in the chain built by Handler: it never
+// calls next (there isn't one), but keeps the func(http.HandlerFunc)
+// http.HandlerFunc shape every other chain() entry uses so it can sit in
+// the same handlers list.
+func (g *Gateway) proxyRequest(http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if m, ok := r.Context().Value("route_match").(*routeMatch); ok && m.proxy != nil {
+			m.proxy.ServeHTTP(w, r)
+			return
 		}
+		g.proxy.ServeHTTP(w, r)
+	}
+}
+
+// streamingResponseModifier wraps SSE/ndjson upstream bodies so they stream
+// through unbuffered while tallying Ollama token counts for the daily quota.
+func (g *Gateway) streamingResponseModifier(resp *http.Response) error {
+	ct := resp.Header.Get("Content-Type")
+	if !strings.Contains(ct, "text/event-stream") && !strings.Contains(ct, "application/x-ndjson") {
+		return nil
+	}
+
+	key := ""
+	if resp.Request != nil {
+		key = resp.Request.Header.Get("X-Api-Key")
+	}
+
+	var body io.ReadCloser = &tokenTallyingReader{
+		rc:  resp.Body,
+		key: key,
+		gw:  g,
+	}
+	if len(g.config.PromptFields) > 0 {
+		body = &constitutionalReader{rc: body, gw: g, req: resp.Request}
+	}
+	resp.Body = body
+	return nil
+}
+
+// tokenTallyingReader passes upstream bytes through unmodified while
+// scanning complete lines for Ollama's eval_count/prompt_eval_count fields,
+// crediting them against the key's daily quota as they arrive.
+type tokenTallyingReader struct {
+	rc  io.ReadCloser
+	key string
+	gw  *Gateway
+	buf bytes.Buffer
+}
+
+func (t *tokenTallyingReader) Read(p []byte) (int, error) {
+	n, err := t.rc.Read(p)
+	if n > 0 {
+		t.buf.Write(p[:n])
+		t.consumeLines()
+	}
+	return n, err
+}
+
+func (t *tokenTallyingReader) consumeLines() {
+	for {
+		line, err := t.buf.ReadString('\n')
+		if err != nil {
+			// Put back the partial line for the next Read.
+			t.buf.Reset()
+			t.buf.WriteString(line)
+			return
+		}
+		payload := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "data:"))
+		if payload == "" || payload == "[DONE]" {
+			continue
+		}
+		var fields struct {
+			EvalCount       int64 `json:"eval_count"`
+			PromptEvalCount int64 `json:"prompt_eval_count"`
+		}
+		if err := json.Unmarshal([]byte(payload), &fields); err == nil {
+			t.gw.recordTokenUsage(t.key, fields.EvalCount+fields.PromptEvalCount)
+		}
+	}
+}
+
+func (t *tokenTallyingReader) Close() error {
+	return t.rc.Close()
+}
+
+// constitutionalReader wraps the upstream body (downstream of
+// tokenTallyingReader) and runs Validator.ValidateOutput over each decoded
+// SSE/ndjson chunk's response text, substituting CorrectedOutput inline or,
+// when EscalationRequired is set, cutting the stream short with an SSE
+// error event.
+type constitutionalReader struct {
+	rc  io.ReadCloser
+	gw  *Gateway
+	req *http.Request
+
+	in  bytes.Buffer // raw bytes not yet split into complete lines
+	out bytes.Buffer // processed bytes ready to hand back to the caller
+	err error        // sticky error from rc, surfaced once out drains
+}
+
+func (c *constitutionalReader) Read(p []byte) (int, error) {
+	buf := make([]byte, 4096)
+	for c.out.Len() == 0 && c.err == nil {
+		n, err := c.rc.Read(buf)
+		if n > 0 {
+			c.in.Write(buf[:n])
+			c.drainLines()
+		}
+		if err != nil && c.err == nil {
+			c.err = err
+			if c.in.Len() > 0 {
+				c.processLine(c.in.String())
+				c.in.Reset()
+			}
+		}
+	}
+	if c.out.Len() > 0 {
+		return c.out.Read(p)
 	}
+	return 0, c.err
 }
 
-func (g *Gateway) proxyRequest(w http.ResponseWriter, r *http.Request) {
-	g.proxy.ServeHTTP(w, r)
+func (c *constitutionalReader) Close() error {
+	return c.rc.Close()
+}
+
+func (c *constitutionalReader) drainLines() {
+	for {
+		line, err := c.in.ReadString('\n')
+		if err != nil {
+			c.in.Reset()
+			c.in.WriteString(line)
+			return
+		}
+		c.processLine(line)
+		if c.err != nil {
+			// processLine hit an escalation and already wrote the cutoff's
+			// terminating SSE error event; any further lines already
+			// buffered from this same read must not reach c.out.
+			return
+		}
+	}
+}
+
+// processLine passes non-JSON and already-clean lines through unmodified,
+// and otherwise runs the decoded response text through ValidateOutput.
+func (c *constitutionalReader) processLine(line string) {
+	trimmed := strings.TrimRight(line, "\n")
+	prefix := ""
+	payload := trimmed
+	if p := strings.TrimPrefix(trimmed, "data:"); p != trimmed {
+		prefix = "data: "
+		payload = strings.TrimSpace(p)
+	}
+
+	if payload == "" || payload == "[DONE]" {
+		c.out.WriteString(line)
+		return
+	}
+
+	var fields map[string]interface{}
+	if err := json.Unmarshal([]byte(payload), &fields); err != nil {
+		c.out.WriteString(line)
+		return
+	}
+	text, setText := outputText(fields)
+	if setText == nil {
+		c.out.WriteString(line)
+		return
+	}
+
+	result := c.gw.validator.ValidateOutput(text)
+	if result.Passed {
+		c.out.WriteString(line)
+		return
+	}
+
+	key := ""
+	if c.req != nil {
+		key = c.req.Header.Get("X-Api-Key")
+	}
+	entry := AuditLog{
+		Timestamp:  time.Now(),
+		KeyPrefix:  maskKey(key),
+		KeyHash:    hashKey(key),
+		Violations: result.Violations,
+		Article:    articleOf(result.Violations),
+	}
+	if c.req != nil {
+		entry.Path = c.req.URL.Path
+		entry.Method = c.req.Method
+	}
+	c.gw.logChan <- entry
+
+	if result.EscalationRequired {
+		c.out.WriteString("event: error\ndata: {\"error\":\"constitutional violation\"}\n\n")
+		c.err = io.EOF
+		return
+	}
+
+	if result.CorrectedOutput != "" {
+		setText(result.CorrectedOutput)
+	}
+	corrected, err := json.Marshal(fields)
+	if err != nil {
+		c.out.WriteString(line)
+		return
+	}
+	c.out.WriteString(prefix)
+	c.out.Write(corrected)
+	c.out.WriteString("\n")
+}
+
+// outputText locates the generated-text field in a decoded Ollama/
+// OpenAI-compatible streaming chunk ("response" for /api/generate,
+// "message.content" or "content" for chat completions), returning it
+// alongside a setter that writes a replacement back into fields.
+func outputText(fields map[string]interface{}) (text string, setText func(string)) {
+	if s, ok := fields["response"].(string); ok {
+		return s, func(v string) { fields["response"] = v }
+	}
+	if msg, ok := fields["message"].(map[string]interface{}); ok {
+		if s, ok := msg["content"].(string); ok {
+			return s, func(v string) { msg["content"] = v }
+		}
+	}
+	if s, ok := fields["content"].(string); ok {
+		return s, func(v string) { fields["content"] = v }
+	}
+	return "", nil
 }
 
 func (g *Gateway) handleHealth(w http.ResponseWriter, r *http.Request) {
@@ -341,18 +1632,28 @@ func (g *Gateway) handleReload() {
 	for range sig {
 		if g.keys != nil {
 			g.keys.Reload()
+			g.recorder.SetKeyStoreSize(g.keys.Count())
+		}
+		if g.router != nil {
+			if err := g.router.Load(g.config.RouterFile); err != nil {
+				log.Printf("router: reload failed, keeping previous table: %v", err)
+			}
 		}
 	}
 }
 
 func (g *Gateway) Shutdown() {
+	if g.bouncerStop != nil {
+		close(g.bouncerStop)
+		close(g.detectorStop)
+	}
 	close(g.logChan)
 }
 
 // Helpers
 
 func loadConfig() *Config {
-	return &Config{
+	cfg := &Config{
 		ListenAddr:     ":8443",
 		UpstreamURL:    "http://localhost:11434",
 		KeysFile:       "keys.txt",
@@ -361,7 +1662,72 @@ func loadConfig() *Config {
 		RateLimit:      10,
 		RateBurst:      20,
 		RequestTimeout: 30 * time.Second,
+		ACMEHTTPAddr:   ":80",
+		ACMECacheDir:   "acme-cache",
+	}
+	if hosts := os.Getenv("RHINO_ACME_HOSTS"); hosts != "" {
+		cfg.ACMEDomains = strings.Split(hosts, ",")
+	}
+	cfg.ACMEEmail = os.Getenv("RHINO_ACME_EMAIL")
+	cfg.ACMECacheBackend = os.Getenv("RHINO_ACME_CACHE_BACKEND") // "redis", or "" for the on-disk default
+
+	cfg.BouncerEnabled = os.Getenv("RHINO_BOUNCER") == "1"
+	cfg.CrowdSecURL = os.Getenv("RHINO_CROWDSEC_URL")
+	cfg.CrowdSecAPIKey = os.Getenv("RHINO_CROWDSEC_KEY")
+	cfg.BouncerInterval = 10 * time.Second
+
+	cfg.RateLimiterBackend = os.Getenv("RHINO_RATELIMIT_BACKEND") // "redis", or "" for in-process
+	cfg.RedisAddr = os.Getenv("RHINO_REDIS_ADDR")
+	cfg.RedisKeyPrefix = os.Getenv("RHINO_REDIS_KEY_PREFIX")
+	cfg.RouteLimits = parseRouteLimits(os.Getenv("RHINO_ROUTE_LIMITS"))
+
+	cfg.AuthSpec = os.Getenv("RHINO_AUTH") // e.g. "cert://,apikey://keys.txt"; empty keeps legacy auth
+
+	cfg.PromptFields = parsePromptFields(os.Getenv("RHINO_PROMPT_FIELDS")) // e.g. "$.prompt,$.messages[*].content"
+
+	fmt.Sscanf(os.Getenv("RHINO_MAX_INFLIGHT"), "%d", &cfg.MaxRequestsInFlight)
+	fmt.Sscanf(os.Getenv("RHINO_MAX_MUTATING_INFLIGHT"), "%d", &cfg.MaxMutatingRequestsInFlight)
+	cfg.LongRunningRequestRE = os.Getenv("RHINO_LONGRUNNING_RE") // empty keeps defaultLongRunningRequestRE
+
+	cfg.RouterFile = os.Getenv("RHINO_ROUTER_FILE") // empty keeps the legacy single-upstream proxy
+
+	return cfg
+}
+
+// parsePromptFields splits the comma-separated RHINO_PROMPT_FIELDS selectors.
+func parsePromptFields(spec string) []string {
+	var fields []string
+	for _, f := range strings.Split(spec, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// parseRouteLimits reads the "<prefix>=<rps>:<burst>,..." format of
+// RHINO_ROUTE_LIMITS, e.g. "/v1/generate=2:5,/v1/embed=10:20".
+func parseRouteLimits(spec string) ratelimit.RouteLimits {
+	var limits ratelimit.RouteLimits
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		prefix, rpsBurst, ok := strings.Cut(entry, "=")
+		rps, burstStr, ok2 := strings.Cut(rpsBurst, ":")
+		if !ok || !ok2 {
+			log.Printf("RHINO_ROUTE_LIMITS: ignoring malformed entry %q", entry)
+			continue
+		}
+		var rpsVal float64
+		var burstVal int
+		fmt.Sscanf(rps, "%f", &rpsVal)
+		fmt.Sscanf(burstStr, "%d", &burstVal)
+		limits = append(limits, ratelimit.RouteLimit{Prefix: prefix, RPS: rpsVal, Burst: burstVal})
 	}
+	return limits
 }
 
 func maskKey(key string) string {
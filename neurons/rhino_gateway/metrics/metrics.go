@@ -0,0 +1,175 @@
+// Package metrics implements Rhino Gateway's Prometheus instrumentation:
+// request counters and latency histograms, per-scope in-flight gauges, an
+// upstream connection-pool proxy gauge, validator violation counters, a
+// rate-limit rejection counter, and a key-store size gauge. Gateway talks
+// to it through the Recorder interface so tests can inject NoopRecorder
+// instead of standing up a real registry.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// llmLatencyBuckets spans 10ms to 120s, wide enough to bucket both a fast
+// /api/tags poll and a multi-minute streaming generation call.
+var llmLatencyBuckets = []float64{
+	0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 90, 120,
+}
+
+// Recorder is the instrumentation surface Gateway calls into.
+type Recorder interface {
+	// ObserveRequest records one completed request's outcome and latency.
+	ObserveRequest(method, pathTemplate string, status int, keyPrefix string, duration time.Duration)
+	// SetInFlight reports the current number of requests being served in scope.
+	SetInFlight(scope string, n int64)
+	// IncUpstreamInFlight/DecUpstreamInFlight bracket a single upstream round trip.
+	IncUpstreamInFlight()
+	DecUpstreamInFlight()
+	// ObserveViolation records a constitutional Validator violation for article.
+	ObserveViolation(article string)
+	// IncRateLimitRejection records one request denied by the rate limiter.
+	IncRateLimitRejection()
+	// SetKeyStoreSize reports how many API keys are currently loaded.
+	SetKeyStoreSize(n int)
+	// Handler serves the Prometheus exposition format for /metrics.
+	Handler() http.Handler
+}
+
+// PromRecorder is the real Recorder, backed by its own prometheus.Registry
+// (not the global DefaultRegisterer, so multiple Gateways in one process —
+// as in tests — don't collide on metric registration).
+type PromRecorder struct {
+	registry *prometheus.Registry
+
+	requests            *prometheus.CounterVec
+	latency             *prometheus.HistogramVec
+	inFlight            *prometheus.GaugeVec
+	upstreamInFlight    prometheus.Gauge
+	violations          *prometheus.CounterVec
+	rateLimitRejections prometheus.Counter
+	keyStoreSize        prometheus.Gauge
+}
+
+// New builds a PromRecorder with the standard Go/process collectors
+// registered alongside Rhino Gateway's own metrics.
+func New() *PromRecorder {
+	reg := prometheus.NewRegistry()
+	reg.MustRegister(collectors.NewGoCollector())
+	reg.MustRegister(collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+
+	factory := promauto.With(reg)
+	return &PromRecorder{
+		registry: reg,
+		requests: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rhino",
+			Name:      "requests_total",
+			Help:      "Total HTTP requests handled by the gateway.",
+		}, []string{"method", "path_template", "status", "key_prefix"}),
+		latency: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "rhino",
+			Name:      "request_duration_seconds",
+			Help:      "Request latency, including upstream inference time.",
+			Buckets:   llmLatencyBuckets,
+		}, []string{"method", "path_template"}),
+		inFlight: factory.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: "rhino",
+			Name:      "requests_in_flight",
+			Help:      "Requests currently being served, by scope.",
+		}, []string{"scope"}),
+		upstreamInFlight: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: "rhino",
+			Name:      "upstream_connections_in_flight",
+			Help:      "Upstream round trips currently in flight, a proxy for http.Transport connection-pool utilization since the stdlib exposes no direct pool stats.",
+		}),
+		violations: factory.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "rhino",
+			Name:      "validator_violations_total",
+			Help:      "Constitutional validator violations, by article.",
+		}, []string{"article"}),
+		rateLimitRejections: factory.NewCounter(prometheus.CounterOpts{
+			Namespace: "rhino",
+			Name:      "rate_limit_rejections_total",
+			Help:      "Requests rejected by the rate limiter.",
+		}),
+		keyStoreSize: factory.NewGauge(prometheus.GaugeOpts{
+			Namespace: "rhino",
+			Name:      "keystore_keys",
+			Help:      "Number of API keys currently loaded from KeysFile.",
+		}),
+	}
+}
+
+// ObserveRequest implements Recorder.
+func (r *PromRecorder) ObserveRequest(method, pathTemplate string, status int, keyPrefix string, duration time.Duration) {
+	statusLabel := strconv.Itoa(status)
+	r.requests.WithLabelValues(method, pathTemplate, statusLabel, keyPrefix).Inc()
+	r.latency.WithLabelValues(method, pathTemplate).Observe(duration.Seconds())
+}
+
+// SetInFlight implements Recorder.
+func (r *PromRecorder) SetInFlight(scope string, n int64) {
+	r.inFlight.WithLabelValues(scope).Set(float64(n))
+}
+
+// IncUpstreamInFlight implements Recorder.
+func (r *PromRecorder) IncUpstreamInFlight() { r.upstreamInFlight.Inc() }
+
+// DecUpstreamInFlight implements Recorder.
+func (r *PromRecorder) DecUpstreamInFlight() { r.upstreamInFlight.Dec() }
+
+// ObserveViolation implements Recorder.
+func (r *PromRecorder) ObserveViolation(article string) {
+	if article == "" {
+		article = "unknown"
+	}
+	r.violations.WithLabelValues(article).Inc()
+}
+
+// IncRateLimitRejection implements Recorder.
+func (r *PromRecorder) IncRateLimitRejection() { r.rateLimitRejections.Inc() }
+
+// SetKeyStoreSize implements Recorder.
+func (r *PromRecorder) SetKeyStoreSize(n int) { r.keyStoreSize.Set(float64(n)) }
+
+// Handler implements Recorder.
+func (r *PromRecorder) Handler() http.Handler {
+	return promhttp.HandlerFor(r.registry, promhttp.HandlerOpts{Registry: r.registry})
+}
+
+// InstrumentedTransport wraps an http.RoundTripper, bracketing every round
+// trip with IncUpstreamInFlight/DecUpstreamInFlight.
+type InstrumentedTransport struct {
+	Next     http.RoundTripper
+	Recorder Recorder
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *InstrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.Recorder.IncUpstreamInFlight()
+	defer t.Recorder.DecUpstreamInFlight()
+	return t.Next.RoundTrip(req)
+}
+
+// NoopRecorder discards everything, for tests that don't care about metrics.
+type NoopRecorder struct{}
+
+func (NoopRecorder) ObserveRequest(method, pathTemplate string, status int, keyPrefix string, duration time.Duration) {
+}
+func (NoopRecorder) SetInFlight(scope string, n int64) {}
+func (NoopRecorder) IncUpstreamInFlight()              {}
+func (NoopRecorder) DecUpstreamInFlight()              {}
+func (NoopRecorder) ObserveViolation(article string)   {}
+func (NoopRecorder) IncRateLimitRejection()            {}
+func (NoopRecorder) SetKeyStoreSize(n int)             {}
+func (NoopRecorder) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPromRecorder_ObserveRequest(t *testing.T) {
+	r := New()
+	r.ObserveRequest("GET", "/api/generate", 200, "abcd****", 50*time.Millisecond)
+
+	rr := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	r.Handler().ServeHTTP(rr, req)
+
+	body := rr.Body.String()
+	if !strings.Contains(body, `rhino_requests_total{key_prefix="abcd****",method="GET",path_template="/api/generate",status="200"} 1`) {
+		t.Fatalf("expected requests_total sample in output, got:\n%s", body)
+	}
+	if !strings.Contains(body, "rhino_request_duration_seconds") {
+		t.Fatalf("expected request_duration_seconds histogram in output, got:\n%s", body)
+	}
+}
+
+func TestPromRecorder_ViolationsAndRejections(t *testing.T) {
+	r := New()
+	r.ObserveViolation("Article II")
+	r.IncRateLimitRejection()
+	r.SetKeyStoreSize(3)
+	r.SetInFlight("readonly", 2)
+
+	rr := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	body := rr.Body.String()
+
+	for _, want := range []string{
+		`rhino_validator_violations_total{article="Article II"} 1`,
+		"rhino_rate_limit_rejections_total 1",
+		"rhino_keystore_keys 3",
+		`rhino_requests_in_flight{scope="readonly"} 2`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("expected %q in output, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestNoopRecorder_DoesNotPanic(t *testing.T) {
+	var r Recorder = NoopRecorder{}
+	r.ObserveRequest("GET", "/x", 200, "", time.Second)
+	r.SetInFlight("readonly", 1)
+	r.IncUpstreamInFlight()
+	r.DecUpstreamInFlight()
+	r.ObserveViolation("Article I")
+	r.IncRateLimitRejection()
+	r.SetKeyStoreSize(1)
+
+	rr := httptest.NewRecorder()
+	r.Handler().ServeHTTP(rr, httptest.NewRequest("GET", "/metrics", nil))
+	if rr.Code != 200 {
+		t.Fatalf("expected 200, got %d", rr.Code)
+	}
+}
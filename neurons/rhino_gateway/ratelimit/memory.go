@@ -0,0 +1,135 @@
+package ratelimit
+
+import (
+	"container/list"
+	"context"
+	"hash/fnv"
+	"sync"
+	"time"
+)
+
+const (
+	defaultShardCount  = 32
+	defaultMaxPerShard = 4096
+)
+
+// bucket is one identity's token bucket, refilled lazily on each Allow call
+// based on elapsed wall-clock time rather than a background ticker.
+type bucket struct {
+	tokens float64
+	rps    float64
+	burst  int
+	last   time.Time
+}
+
+// lruEntry is the value stored in a shard's list.Element, carrying the key
+// alongside the bucket so eviction can delete it from the shard's map too.
+type lruEntry struct {
+	key string
+	b   *bucket
+}
+
+// shard is one lock-striped, bounded LRU of buckets.
+type shard struct {
+	mu      sync.Mutex
+	buckets map[string]*list.Element
+	order   *list.List // front = most recently used
+	max     int
+}
+
+// MemoryStore is an in-process ratelimit.Store sharded by key hash so a hot
+// identity doesn't serialize requests for every other one, and bounded per
+// shard with LRU eviction so a flood of distinct unauthenticated client IPs
+// can't grow the map without limit.
+type MemoryStore struct {
+	shards []*shard
+}
+
+// NewMemoryStore creates a MemoryStore whose shards each hold at most
+// maxEntries/defaultShardCount buckets (never fewer than one) before
+// evicting their least-recently-used ones, so the total is about maxEntries
+// and at least defaultShardCount. maxEntries <= 0 falls back to a sane
+// default.
+func NewMemoryStore(maxEntries int) *MemoryStore {
+	if maxEntries <= 0 {
+		maxEntries = defaultMaxPerShard * defaultShardCount
+	}
+	perShard := maxEntries / defaultShardCount
+	if perShard < 1 {
+		perShard = 1
+	}
+
+	shards := make([]*shard, defaultShardCount)
+	for i := range shards {
+		shards[i] = &shard{
+			buckets: make(map[string]*list.Element),
+			order:   list.New(),
+			max:     perShard,
+		}
+	}
+	return &MemoryStore{shards: shards}
+}
+
+func (m *MemoryStore) shardFor(key string) *shard {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return m.shards[h.Sum32()%uint32(len(m.shards))]
+}
+
+// Allow implements Store.
+func (m *MemoryStore) Allow(ctx context.Context, key string, rps float64, burst int) (Decision, error) {
+	s := m.shardFor(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	b, ok := s.get(key)
+	if !ok {
+		b = &bucket{tokens: float64(burst), rps: rps, burst: burst, last: now}
+		s.put(key, b)
+	}
+
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.rps, b.burst = rps, burst // config may change key's limits between calls (e.g. hot-reloaded keys.txt)
+	b.tokens += elapsed * rps
+	if b.tokens > float64(burst) {
+		b.tokens = float64(burst)
+	}
+
+	if b.tokens < 1 {
+		retryAfter := time.Second
+		if rps > 0 {
+			retryAfter = time.Duration((1 - b.tokens) / rps * float64(time.Second))
+		}
+		return Decision{Allowed: false, Remaining: 0, RetryAfter: retryAfter}, nil
+	}
+
+	b.tokens--
+	return Decision{Allowed: true, Remaining: b.tokens}, nil
+}
+
+// get returns key's bucket and marks it most-recently-used, if present.
+func (s *shard) get(key string) (*bucket, bool) {
+	el, ok := s.buckets[key]
+	if !ok {
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	return el.Value.(*lruEntry).b, true
+}
+
+// put inserts b under key and evicts the least-recently-used entry if the
+// shard is now over capacity.
+func (s *shard) put(key string, b *bucket) {
+	el := s.order.PushFront(&lruEntry{key: key, b: b})
+	s.buckets[key] = el
+	for len(s.buckets) > s.max {
+		oldest := s.order.Back()
+		if oldest == nil {
+			return
+		}
+		s.order.Remove(oldest)
+		delete(s.buckets, oldest.Value.(*lruEntry).key)
+	}
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"testing"
+)
+
+// sameShardKey searches for a key (other than avoid) that hashes into the
+// same shard as avoid, rather than guessing from a fixed list — FNV-32a
+// doesn't guarantee any particular short string lands in a given shard.
+func sameShardKey(t *testing.T, s *MemoryStore, sh *shard, avoid string) string {
+	t.Helper()
+	for i := 0; ; i++ {
+		k := fmt.Sprintf("probe-%d", i)
+		if k != avoid && s.shardFor(k) == sh {
+			return k
+		}
+	}
+}
+
+func TestMemoryStore_AllowBurst(t *testing.T) {
+	s := NewMemoryStore(0)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		d, err := s.Allow(ctx, "key-a", 1, 3)
+		if err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+		if !d.Allowed {
+			t.Fatalf("request %d: expected allowed within burst", i)
+		}
+	}
+
+	d, err := s.Allow(ctx, "key-a", 1, 3)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if d.Allowed {
+		t.Fatal("expected 4th request to exceed burst of 3")
+	}
+	if d.RetryAfter <= 0 {
+		t.Fatal("expected a positive RetryAfter when denied")
+	}
+}
+
+func TestMemoryStore_SeparateKeysIndependent(t *testing.T) {
+	s := NewMemoryStore(0)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := s.Allow(ctx, "key-a", 1, 2); err != nil {
+			t.Fatalf("Allow: %v", err)
+		}
+	}
+	d, err := s.Allow(ctx, "key-a", 1, 2)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if d.Allowed {
+		t.Fatal("expected key-a to be exhausted")
+	}
+
+	d, err = s.Allow(ctx, "key-b", 1, 2)
+	if err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if !d.Allowed {
+		t.Fatal("expected key-b to have its own independent bucket")
+	}
+}
+
+func TestMemoryStore_EvictsLeastRecentlyUsed(t *testing.T) {
+	s := NewMemoryStore(defaultShardCount) // 1 entry per shard
+	ctx := context.Background()
+
+	sh := s.shardFor("evict-me")
+	if _, err := s.Allow(ctx, "evict-me", 1, 5); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if _, ok := sh.buckets["evict-me"]; !ok {
+		t.Fatal("expected first key to be tracked")
+	}
+
+	// A second key landing in the same shard evicts the first once the
+	// shard's capacity (1) is exceeded.
+	other := sameShardKey(t, s, sh, "evict-me")
+	if _, err := s.Allow(ctx, other, 1, 5); err != nil {
+		t.Fatalf("Allow: %v", err)
+	}
+	if _, ok := sh.buckets["evict-me"]; ok {
+		t.Fatal("expected the least-recently-used key to be evicted from its shard")
+	}
+}
+
+func TestRouteLimits_Match(t *testing.T) {
+	rl := RouteLimits{
+		{Prefix: "/", RPS: 1, Burst: 1},
+		{Prefix: "/v1/generate", RPS: 2, Burst: 2},
+	}
+
+	match, ok := rl.Match("/v1/generate/stream")
+	if !ok || match.Prefix != "/v1/generate" {
+		t.Fatalf("expected longest-prefix match on /v1/generate, got %+v (ok=%v)", match, ok)
+	}
+
+	match, ok = rl.Match("/health")
+	if !ok || match.Prefix != "/" {
+		t.Fatalf("expected fallback match on /, got %+v (ok=%v)", match, ok)
+	}
+}
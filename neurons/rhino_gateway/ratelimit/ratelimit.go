@@ -0,0 +1,63 @@
+// Package ratelimit provides a pluggable per-identity token-bucket limiter
+// for Rhino Gateway. A single in-process rate.Limiter can't be shared across
+// a fleet of gateway pods and leaks memory if its key space churns (e.g.
+// per-IP limiting of unauthenticated traffic), so callers go through the
+// Store interface instead: MemoryStore bounds itself with a sharded LRU,
+// RedisStore shares state across pods via an atomic Lua script.
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Decision is the outcome of a single Allow call.
+type Decision struct {
+	Allowed bool
+	// Remaining is the number of tokens left in the bucket after this
+	// call, for the X-RateLimit-Remaining header.
+	Remaining float64
+	// RetryAfter is how long the caller should wait before trying again;
+	// only meaningful when Allowed is false.
+	RetryAfter time.Duration
+}
+
+// Store is a token-bucket limiter keyed by an arbitrary identity string
+// (an API key hash, "ip:"+addr for unauthenticated traffic, etc). Buckets
+// are created lazily on first use with the given rps/burst.
+type Store interface {
+	// Allow consumes one token from key's bucket, creating it with rps/burst
+	// if this is the first call for key.
+	Allow(ctx context.Context, key string, rps float64, burst int) (Decision, error)
+}
+
+// RouteLimit is one route-prefix's default rate/burst, applied when the
+// caller's API key has no per-key override.
+type RouteLimit struct {
+	Prefix string
+	RPS    float64
+	Burst  int
+}
+
+// RouteLimits is an ordered list of RouteLimit matched longest-prefix-first,
+// so a specific route (e.g. "/v1/generate") can carry a tighter limit than
+// the catch-all "/" default.
+type RouteLimits []RouteLimit
+
+// Match returns the RouteLimit whose Prefix is the longest match for path,
+// and ok=false if none match.
+func (rl RouteLimits) Match(path string) (RouteLimit, bool) {
+	best := -1
+	var match RouteLimit
+	for _, r := range rl {
+		if len(r.Prefix) > best && hasPrefix(path, r.Prefix) {
+			best = len(r.Prefix)
+			match = r
+		}
+	}
+	return match, best >= 0
+}
+
+func hasPrefix(path, prefix string) bool {
+	return len(path) >= len(prefix) && path[:len(prefix)] == prefix
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// tokenBucketScript implements the same token-bucket as MemoryStore, but as
+// a single atomic Lua script so a fleet of gateway pods sharing one Redis
+// instance see a consistent view of each identity's remaining tokens
+// instead of racing each other. PEXPIRE bounds the key's lifetime so an
+// identity that stops sending requests ages out of Redis on its own,
+// mirroring MemoryStore's LRU bound.
+const tokenBucketScript = `
+local key = KEYS[1]
+local rps = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now_ms = tonumber(ARGV[3])
+local ttl_ms = tonumber(ARGV[4])
+
+local tokens = tonumber(redis.call("HGET", key, "tokens"))
+local last_ms = tonumber(redis.call("HGET", key, "ts"))
+if tokens == nil then
+	tokens = burst
+	last_ms = now_ms
+end
+
+local elapsed = math.max(0, now_ms - last_ms)
+tokens = math.min(burst, tokens + (elapsed / 1000.0) * rps)
+
+local allowed = 0
+if tokens >= 1 then
+	allowed = 1
+	tokens = tokens - 1
+end
+
+redis.call("HSET", key, "tokens", tokens, "ts", now_ms)
+redis.call("PEXPIRE", key, ttl_ms)
+
+return {allowed, tostring(tokens)}
+`
+
+// RedisStore is a ratelimit.Store backed by Redis, so every Rhino Gateway
+// pod in a fleet enforces the same per-identity limits instead of each pod
+// keeping its own independent bucket.
+type RedisStore struct {
+	client    *redis.Client
+	script    *redis.Script
+	keyPrefix string
+}
+
+// NewRedisStore creates a RedisStore using client, namespacing its keys
+// under keyPrefix (e.g. "cynapse:ratelimit:") so it can share a Redis
+// instance with unrelated data.
+func NewRedisStore(client *redis.Client, keyPrefix string) *RedisStore {
+	return &RedisStore{
+		client:    client,
+		script:    redis.NewScript(tokenBucketScript),
+		keyPrefix: keyPrefix,
+	}
+}
+
+// Allow implements Store.
+func (r *RedisStore) Allow(ctx context.Context, key string, rps float64, burst int) (Decision, error) {
+	now := time.Now()
+	// A bucket with no traffic for 2x its own refill time is back at full
+	// burst anyway, so that's as long as Redis needs to remember it.
+	ttl := 2 * time.Second
+	if rps > 0 {
+		ttl = time.Duration(2*float64(burst)/rps*float64(time.Second)) + time.Second
+	}
+
+	res, err := r.script.Run(ctx, r.client, []string{r.keyPrefix + key},
+		rps, burst, now.UnixMilli(), ttl.Milliseconds()).Result()
+	if err != nil {
+		return Decision{}, fmt.Errorf("ratelimit: redis: %w", err)
+	}
+
+	vals, ok := res.([]interface{})
+	if !ok || len(vals) != 2 {
+		return Decision{}, fmt.Errorf("ratelimit: redis: unexpected script result %v", res)
+	}
+	allowed, _ := vals[0].(int64)
+	var remaining float64
+	fmt.Sscanf(fmt.Sprint(vals[1]), "%f", &remaining)
+
+	d := Decision{Allowed: allowed == 1, Remaining: remaining}
+	if !d.Allowed {
+		if rps > 0 {
+			d.RetryAfter = time.Duration((1 - remaining) / rps * float64(time.Second))
+		} else {
+			d.RetryAfter = time.Second
+		}
+	}
+	return d, nil
+}
@@ -0,0 +1,167 @@
+// Package router lets one Rhino Gateway instance front multiple model
+// backends (e.g. llama.cpp on :11434, vLLM on :8000, a separate embeddings
+// service) with distinct auth and timeout policies, instead of the single
+// UpstreamURL every request used to share. A Table is loaded from a
+// YAML or JSON file of {host, path_prefix} -> upstream rules and is
+// hot-reloadable on SIGHUP alongside KeyStore.
+package router
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Route is one entry in the routing table: requests whose Host (if set)
+// and URL path prefix match are proxied to Upstream, subject to the
+// optional method/key restrictions below.
+type Route struct {
+	// Host, if set, must equal r.Host (ignoring a ":port" suffix). Empty
+	// matches any host, so a single-tenant deployment can omit it entirely.
+	Host string `yaml:"host,omitempty" json:"host,omitempty"`
+	// PathPrefix is matched against r.URL.Path; the route with the longest
+	// matching prefix wins, mirroring ratelimit.RouteLimits.
+	PathPrefix string `yaml:"path_prefix" json:"path_prefix"`
+	// Upstream is the backend base URL, e.g. "http://localhost:11434".
+	Upstream string `yaml:"upstream" json:"upstream"`
+	// Methods restricts which HTTP methods this route accepts; empty means
+	// all methods are allowed.
+	Methods []string `yaml:"methods,omitempty" json:"methods,omitempty"`
+	// RequiredKeys, if non-empty, lists the only X-Api-Key values (or
+	// identities from a pluggable auth.Provider) permitted to use this
+	// route, layering a per-backend allowlist on top of Gateway's normal
+	// authentication.
+	RequiredKeys []string `yaml:"required_keys,omitempty" json:"required_keys,omitempty"`
+	// LongRunning exempts this route from the in-flight caps and the
+	// per-request write timeout, the same treatment Config.LongRunningRequestRE
+	// gives streaming generation endpoints today.
+	LongRunning bool `yaml:"long_running,omitempty" json:"long_running,omitempty"`
+	// Timeout overrides Config.RequestTimeout for this route; zero keeps
+	// the gateway-wide default.
+	Timeout time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"`
+	// RewritePrefix, if set, replaces PathPrefix at the front of the
+	// forwarded request path, e.g. PathPrefix "/embeddings" and
+	// RewritePrefix "/v1" turns "/embeddings/foo" into "/v1/foo" upstream.
+	RewritePrefix string `yaml:"rewrite_prefix,omitempty" json:"rewrite_prefix,omitempty"`
+}
+
+// matchesHostPath reports whether host and path select this route,
+// independent of Methods — callers check AllowsMethod separately so an
+// otherwise-matching route yields a 405 rather than falling through to a
+// less specific one.
+func (rt Route) matchesHostPath(host, path string) bool {
+	if rt.Host != "" && hostWithoutPort(host) != rt.Host {
+		return false
+	}
+	return strings.HasPrefix(path, rt.PathPrefix)
+}
+
+// AllowsMethod reports whether method is permitted by the route; an empty
+// Methods list allows everything.
+func (rt Route) AllowsMethod(method string) bool {
+	if len(rt.Methods) == 0 {
+		return true
+	}
+	for _, m := range rt.Methods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostWithoutPort(host string) string {
+	if i := strings.LastIndexByte(host, ':'); i != -1 {
+		return host[:i]
+	}
+	return host
+}
+
+// Table is a hot-reloadable routing table backed by a pool of
+// *httputil.ReverseProxy, one per distinct upstream, all sharing a single
+// tuned http.RoundTripper so backends don't each pay their own dial/TLS
+// handshake cost.
+type Table struct {
+	transport http.RoundTripper // nil uses httputil.ReverseProxy's default (http.DefaultTransport)
+
+	mu      sync.RWMutex
+	routes  []Route
+	proxies map[string]*httputil.ReverseProxy
+}
+
+// New builds an empty Table sharing transport across every pooled proxy.
+// Callers load routes via Load before Match returns anything.
+func New(transport http.RoundTripper) *Table {
+	return &Table{transport: transport, proxies: make(map[string]*httputil.ReverseProxy)}
+}
+
+// Load (re)reads path — YAML unless it ends in ".json" — and atomically
+// replaces the routing table and proxy pool. An error leaves the previous
+// table in place, so a bad SIGHUP reload doesn't take the gateway down.
+func (t *Table) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("router: %w", err)
+	}
+
+	var routes []Route
+	if strings.HasSuffix(path, ".json") {
+		err = json.Unmarshal(data, &routes)
+	} else {
+		err = yaml.Unmarshal(data, &routes)
+	}
+	if err != nil {
+		return fmt.Errorf("router: parsing %s: %w", path, err)
+	}
+
+	proxies := make(map[string]*httputil.ReverseProxy, len(routes))
+	for _, rt := range routes {
+		if _, ok := proxies[rt.Upstream]; ok {
+			continue
+		}
+		upstream, err := url.Parse(rt.Upstream)
+		if err != nil {
+			return fmt.Errorf("router: route %q: invalid upstream %q: %w", rt.PathPrefix, rt.Upstream, err)
+		}
+		proxy := httputil.NewSingleHostReverseProxy(upstream)
+		proxy.FlushInterval = -1
+		proxy.Transport = t.transport
+		proxies[rt.Upstream] = proxy
+	}
+
+	t.mu.Lock()
+	t.routes = routes
+	t.proxies = proxies
+	t.mu.Unlock()
+	return nil
+}
+
+// Match returns the longest-PathPrefix route matching host/path along with
+// its pooled proxy, and ok=false if nothing matches. It does not consider
+// Methods; check Route.AllowsMethod to distinguish "no such route" (404)
+// from "route exists but rejects this method" (405).
+func (t *Table) Match(host, path string) (Route, *httputil.ReverseProxy, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	best := -1
+	var match Route
+	for _, rt := range t.routes {
+		if len(rt.PathPrefix) > best && rt.matchesHostPath(host, path) {
+			best = len(rt.PathPrefix)
+			match = rt
+		}
+	}
+	if best < 0 {
+		return Route{}, nil, false
+	}
+	return match, t.proxies[match.Upstream], true
+}
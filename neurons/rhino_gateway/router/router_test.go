@@ -0,0 +1,110 @@
+package router
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeRoutes(t *testing.T, yamlBody string) string {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "routes.yaml")
+	if err := os.WriteFile(path, []byte(yamlBody), 0o600); err != nil {
+		t.Fatalf("write routes file: %v", err)
+	}
+	return path
+}
+
+func TestTable_MatchLongestPrefix(t *testing.T) {
+	path := writeRoutes(t, `
+- path_prefix: /
+  upstream: http://localhost:11434
+- path_prefix: /v1/embeddings
+  upstream: http://localhost:8001
+  methods: [POST]
+`)
+	tbl := New(nil)
+	if err := tbl.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	rt, proxy, ok := tbl.Match("gateway.local", "/v1/embeddings/foo")
+	if !ok {
+		t.Fatal("expected a match for /v1/embeddings/foo")
+	}
+	if rt.Upstream != "http://localhost:8001" {
+		t.Fatalf("expected the more specific route, got upstream %q", rt.Upstream)
+	}
+	if proxy == nil {
+		t.Fatal("expected a pooled proxy for the matched route")
+	}
+	if rt.AllowsMethod("GET") {
+		t.Fatal("expected GET to be rejected by the POST-only route")
+	}
+
+	rt, _, ok = tbl.Match("gateway.local", "/api/tags")
+	if !ok || rt.Upstream != "http://localhost:11434" {
+		t.Fatalf("expected fallback to the catch-all route, got %+v ok=%v", rt, ok)
+	}
+}
+
+func TestTable_HostScoping(t *testing.T) {
+	path := writeRoutes(t, `
+- host: embeddings.internal
+  path_prefix: /
+  upstream: http://localhost:8001
+`)
+	tbl := New(nil)
+	if err := tbl.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if _, _, ok := tbl.Match("other.internal:8443", "/anything"); ok {
+		t.Fatal("expected no match for an unrelated host")
+	}
+	if _, _, ok := tbl.Match("embeddings.internal:8443", "/anything"); !ok {
+		t.Fatal("expected a match once the host (ignoring port) matches")
+	}
+}
+
+func TestTable_ReusesProxyPerUpstream(t *testing.T) {
+	path := writeRoutes(t, `
+- path_prefix: /a
+  upstream: http://localhost:9000
+- path_prefix: /b
+  upstream: http://localhost:9000
+`)
+	tbl := New(nil)
+	if err := tbl.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	_, proxyA, _ := tbl.Match("", "/a/x")
+	_, proxyB, _ := tbl.Match("", "/b/x")
+	if proxyA != proxyB {
+		t.Fatal("expected routes sharing an upstream to share one pooled proxy")
+	}
+}
+
+func TestTable_LoadInvalidKeepsPreviousTable(t *testing.T) {
+	good := writeRoutes(t, `
+- path_prefix: /
+  upstream: http://localhost:11434
+`)
+	tbl := New(nil)
+	if err := tbl.Load(good); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(bad, []byte(": not valid yaml :::"), 0o600); err != nil {
+		t.Fatalf("write bad routes file: %v", err)
+	}
+	if err := tbl.Load(bad); err == nil {
+		t.Fatal("expected Load to fail on malformed input")
+	}
+
+	if _, _, ok := tbl.Match("", "/api/tags"); !ok {
+		t.Fatal("expected the previous table to still be in effect after a failed reload")
+	}
+}
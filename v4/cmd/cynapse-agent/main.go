@@ -0,0 +1,211 @@
+// Cynapse Agent — a detached worker that dials a HiveMind engine, advertises
+// which node types it can run, and long-polls for work. Runs wherever the
+// main engine shouldn't: a DMZ host for meerkat scans, a target container
+// for octopus checks.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/Alartist40/cynapse/internal/core"
+	"github.com/Alartist40/cynapse/internal/neurons/meerkat"
+	"github.com/Alartist40/cynapse/internal/neurons/octopus"
+)
+
+// runner executes a delegated task locally and returns its output.
+type runner func(inputs, config map[string]interface{}) (map[string]interface{}, error)
+
+func main() {
+	engineURL := flag.String("engine", "http://localhost:8090/worker", "base URL of the engine's worker endpoint")
+	token := flag.String("token", "", "shared agent auth token")
+	platform := flag.String("platform", "", "free-form platform string reported at registration")
+	maxProcs := flag.Int("max-procs", 2, "maximum tasks this agent runs concurrently")
+	flag.Parse()
+
+	runners := map[string]runner{
+		"meerkat_remote": neuronRunner(meerkat.New()),
+		"octopus_remote": neuronRunner(octopus.New()),
+	}
+
+	nodeTypes := make([]string, 0, len(runners))
+	for nt := range runners {
+		nodeTypes = append(nodeTypes, nt)
+	}
+
+	a := &agent{
+		client:    &http.Client{Timeout: 30 * time.Second},
+		base:      *engineURL,
+		token:     *token,
+		platform:  *platform,
+		nodeTypes: nodeTypes,
+		maxProcs:  *maxProcs,
+		runners:   runners,
+	}
+
+	if err := a.register(); err != nil {
+		fmt.Fprintf(os.Stderr, "cynapse-agent: register: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Printf("cynapse-agent: registered as %s, node types %v\n", a.id, nodeTypes)
+
+	go a.heartbeatLoop()
+
+	sem := make(chan struct{}, a.maxProcs)
+	for {
+		task, ok := a.poll()
+		if !ok {
+			continue
+		}
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem }()
+			a.run(task)
+		}()
+	}
+}
+
+// neuronRunner adapts a core.Neuron into a runner by stringifying inputs
+// into core.Task.Params, mirroring hivemind.NeuronHandler's convention. The
+// node's config supplies "operation"; everything else in inputs becomes a
+// task param.
+func neuronRunner(n core.Neuron) runner {
+	return func(inputs, config map[string]interface{}) (map[string]interface{}, error) {
+		operation, _ := config["operation"].(string)
+
+		params := make(map[string]string, len(inputs))
+		for k, v := range inputs {
+			params[k] = fmt.Sprintf("%v", v)
+		}
+
+		result, err := n.Execute(context.Background(), core.Task{
+			NeuronID:  n.ID(),
+			Operation: operation,
+			Params:    params,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return map[string]interface{}{
+			"output":     result.Output,
+			"success":    result.Success,
+			"confidence": result.Confidence,
+		}, nil
+	}
+}
+
+type agent struct {
+	client    *http.Client
+	base      string
+	token     string
+	platform  string
+	nodeTypes []string
+	maxProcs  int
+	id        string
+	runners   map[string]runner
+}
+
+func (a *agent) register() error {
+	body, _ := json.Marshal(map[string]interface{}{
+		"token":      a.token,
+		"platform":   a.platform,
+		"node_types": a.nodeTypes,
+		"max_procs":  a.maxProcs,
+	})
+	resp, err := a.client.Post(a.base+"/register", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	var out struct {
+		AgentID string `json:"agent_id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return err
+	}
+	a.id = out.AgentID
+	return nil
+}
+
+func (a *agent) heartbeatLoop() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		body, _ := json.Marshal(map[string]string{"agent_id": a.id})
+		resp, err := a.client.Post(a.base+"/heartbeat", "application/json", bytes.NewReader(body))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cynapse-agent: heartbeat: %v\n", err)
+			continue
+		}
+		resp.Body.Close()
+	}
+}
+
+type remoteTask struct {
+	ID     string                 `json:"id"`
+	Type   string                 `json:"type"`
+	Inputs map[string]interface{} `json:"inputs"`
+	Config map[string]interface{} `json:"config"`
+}
+
+func (a *agent) poll() (remoteTask, bool) {
+	body, _ := json.Marshal(map[string]interface{}{
+		"agent_id":   a.id,
+		"node_types": a.nodeTypes,
+	})
+	resp, err := a.client.Post(a.base+"/poll", "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cynapse-agent: poll: %v\n", err)
+		time.Sleep(time.Second)
+		return remoteTask{}, false
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Task *remoteTask `json:"task"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil || out.Task == nil {
+		return remoteTask{}, false
+	}
+	return *out.Task, true
+}
+
+func (a *agent) run(task remoteTask) {
+	run, ok := a.runners[task.Type]
+	result := map[string]interface{}{}
+	errMsg := ""
+
+	if !ok {
+		errMsg = fmt.Sprintf("no runner registered for node type %q", task.Type)
+	} else {
+		out, err := run(task.Inputs, task.Config)
+		if err != nil {
+			errMsg = err.Error()
+		} else {
+			result = out
+		}
+	}
+
+	body, _ := json.Marshal(map[string]interface{}{
+		"task_id":  task.ID,
+		"agent_id": a.id,
+		"output":   result,
+		"error":    errMsg,
+	})
+	resp, err := a.client.Post(a.base+"/result", "application/json", bytes.NewReader(body))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "cynapse-agent: post result: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+}
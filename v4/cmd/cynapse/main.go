@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 
+	"github.com/Alartist40/cynapse/internal/agents"
 	"github.com/Alartist40/cynapse/internal/bridge"
 	"github.com/Alartist40/cynapse/internal/hivemind"
 	"github.com/Alartist40/cynapse/internal/neurons/bat"
@@ -15,6 +16,7 @@ import (
 	"github.com/Alartist40/cynapse/internal/neurons/meerkat"
 	"github.com/Alartist40/cynapse/internal/neurons/octopus"
 	"github.com/Alartist40/cynapse/internal/neurons/wolverine"
+	"github.com/Alartist40/cynapse/internal/store"
 	"github.com/Alartist40/cynapse/internal/tui"
 	tea "github.com/charmbracelet/bubbletea"
 )
@@ -73,8 +75,25 @@ func main() {
 		engine.RegisterNeuron(owlBridge)
 	}
 
+	// Open the conversation store. A failure here shouldn't block chatting,
+	// it just means history won't survive a restart.
+	convStore, err := store.Open("./cynapse_conversations.db")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to open conversation store: %v\n", err)
+	} else {
+		defer convStore.Close()
+	}
+
+	// Load configured agents. Missing agents.yaml just means the
+	// "agent"/"/agent" command has nothing to switch to.
+	agentsReg, err := agents.Load("./agents.yaml")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: Failed to load agents.yaml: %v\n", err)
+		agentsReg = nil
+	}
+
 	// Launch TUI
-	p := tea.NewProgram(tui.New(engine), tea.WithAltScreen())
+	p := tea.NewProgram(tui.NewWindowManager(engine, convStore, agentsReg), tea.WithAltScreen())
 	if _, err := p.Run(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
@@ -0,0 +1,62 @@
+// Package agents loads named assistant personas — a system prompt, the
+// neuron they run on, and the subset of IT modules they're allowed to
+// call — from agents.yaml, so the TUI's palette/`/agent` command can
+// switch between them instead of always talking to the default neuron.
+package agents
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Agent is a named persona: a system prompt prepended to every task sent
+// to Neuron, restricted to calling only the IT modules listed in Tools.
+type Agent struct {
+	Name         string   `yaml:"name"`
+	SystemPrompt string   `yaml:"system_prompt"`
+	Neuron       string   `yaml:"neuron"`
+	Tools        []string `yaml:"tools"` // IT module IDs this agent may invoke
+}
+
+// Registry holds every agent loaded from agents.yaml, keyed by name.
+type Registry struct {
+	agents map[string]Agent
+}
+
+// Load reads and parses the agents.yaml file at path.
+func Load(path string) (*Registry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("agents: read %s: %w", path, err)
+	}
+
+	var cfg struct {
+		Agents []Agent `yaml:"agents"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("agents: parse %s: %w", path, err)
+	}
+
+	reg := &Registry{agents: make(map[string]Agent, len(cfg.Agents))}
+	for _, a := range cfg.Agents {
+		reg.agents[a.Name] = a
+	}
+	return reg, nil
+}
+
+// Get returns the agent with the given name, if one was loaded.
+func (r *Registry) Get(name string) (Agent, bool) {
+	a, ok := r.agents[name]
+	return a, ok
+}
+
+// List returns every loaded agent.
+func (r *Registry) List() []Agent {
+	agents := make([]Agent, 0, len(r.agents))
+	for _, a := range r.agents {
+		agents = append(agents, a)
+	}
+	return agents
+}
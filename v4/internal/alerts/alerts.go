@@ -0,0 +1,94 @@
+// Package alerts lets neurons page a human when something worth their
+// attention happens — a canary trap firing, a wolverine critical finding,
+// an overly permissive beaver rule — instead of only bumping an in-memory
+// counter that nobody notices without polling the TUI.
+package alerts
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Severity ranks how urgently an Alert should be surfaced.
+type Severity string
+
+const (
+	SeverityInfo     Severity = "info"
+	SeverityLow      Severity = "low"
+	SeverityMedium   Severity = "medium"
+	SeverityHigh     Severity = "high"
+	SeverityCritical Severity = "critical"
+)
+
+// Alert is a single structured event a Sink can forward to a human channel.
+type Alert struct {
+	Severity  Severity          `json:"severity"`
+	Source    string            `json:"source"` // neuron ID that raised it
+	Message   string            `json:"message"`
+	Timestamp time.Time         `json:"timestamp"`
+	Details   map[string]string `json:"details,omitempty"`
+}
+
+// Sink delivers an Alert to wherever a human will actually see it.
+type Sink interface {
+	Emit(ctx context.Context, alert Alert) error
+}
+
+// Emitter is implemented by neurons that want a sink wired in automatically
+// when registered with hivemind.Engine.RegisterNeuron.
+type Emitter interface {
+	SetAlertSink(Sink)
+}
+
+// Bus fans an Alert out to every registered Sink and is itself a Sink, so
+// it can be handed to a neuron exactly like a single concrete sink.
+type Bus struct {
+	mu    sync.RWMutex
+	sinks []Sink
+}
+
+// NewBus returns an empty Bus; sinks are added with Register.
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Register adds a sink that future Emit calls will fan out to.
+func (b *Bus) Register(sink Sink) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sinks = append(b.sinks, sink)
+}
+
+// Emit stamps alert with a timestamp if it doesn't have one, then delivers
+// it to every registered sink concurrently. It returns the first sink error
+// encountered, if any, but every sink still runs regardless of the others'
+// outcome — a broken webhook shouldn't silence the JSONL log.
+func (b *Bus) Emit(ctx context.Context, alert Alert) error {
+	if alert.Timestamp.IsZero() {
+		alert.Timestamp = time.Now()
+	}
+
+	b.mu.RLock()
+	sinks := make([]Sink, len(b.sinks))
+	copy(sinks, b.sinks)
+	b.mu.RUnlock()
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(sinks))
+	for i, sink := range sinks {
+		wg.Add(1)
+		go func(i int, sink Sink) {
+			defer wg.Done()
+			errs[i] = sink.Emit(ctx, alert)
+		}(i, sink)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
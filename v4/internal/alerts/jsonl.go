@@ -0,0 +1,43 @@
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// JSONLSink appends each Alert as one line of JSON to w.
+type JSONLSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLSink wraps an already-open writer (e.g. os.Stdout).
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{w: w}
+}
+
+// NewJSONLFileSink opens (creating and appending to) path for JSONL alert
+// logging.
+func NewJSONLFileSink(path string) (*JSONLSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("alerts: open %s: %w", path, err)
+	}
+	return NewJSONLSink(f), nil
+}
+
+func (s *JSONLSink) Emit(_ context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("alerts: marshal: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = fmt.Fprintf(s.w, "%s\n", body)
+	return err
+}
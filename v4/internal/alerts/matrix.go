@@ -0,0 +1,45 @@
+package alerts
+
+import (
+	"context"
+	"fmt"
+
+	"maunium.net/go/mautrix"
+	"maunium.net/go/mautrix/event"
+	"maunium.net/go/mautrix/id"
+)
+
+// MatrixSink pages a human by posting each Alert into a Matrix room as a
+// formatted m.room.message, authenticating as a bot account via a
+// long-lived access token.
+type MatrixSink struct {
+	client *mautrix.Client
+	room   id.RoomID
+}
+
+// NewMatrixSink logs into homeserverURL as userID using accessToken and
+// joins roomID so subsequent Emit calls can post into it.
+func NewMatrixSink(ctx context.Context, homeserverURL, userID, accessToken, roomID string) (*MatrixSink, error) {
+	client, err := mautrix.NewClient(homeserverURL, id.UserID(userID), accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("alerts: matrix client: %w", err)
+	}
+
+	room := id.RoomID(roomID)
+	if _, err := client.JoinRoom(ctx, string(room), nil); err != nil {
+		return nil, fmt.Errorf("alerts: join room %s: %w", room, err)
+	}
+
+	return &MatrixSink{client: client, room: room}, nil
+}
+
+func (s *MatrixSink) Emit(ctx context.Context, alert Alert) error {
+	content := event.MessageEventContent{
+		MsgType: event.MsgText,
+		Body:    fmt.Sprintf("[%s] %s: %s", alert.Severity, alert.Source, alert.Message),
+	}
+	if _, err := s.client.SendMessageEvent(ctx, s.room, event.EventMessage, &content); err != nil {
+		return fmt.Errorf("alerts: send message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// WebhookSink POSTs each Alert as JSON to a configured URL, signing the
+// body with HMAC-SHA256 so the receiver can reject spoofed alerts.
+type WebhookSink struct {
+	URL    string
+	Secret []byte
+	Client *http.Client
+}
+
+// NewWebhookSink builds a WebhookSink posting to url, signed with secret.
+func NewWebhookSink(url string, secret []byte) *WebhookSink {
+	return &WebhookSink{URL: url, Secret: secret, Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (s *WebhookSink) Emit(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("alerts: marshal: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("alerts: build request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Cynapse-Signature", signBody(s.Secret, body))
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("alerts: webhook post: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alerts: webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+func signBody(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
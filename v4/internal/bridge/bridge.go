@@ -8,39 +8,57 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log"
 	"os/exec"
 	"sync"
+	"sync/atomic"
 
 	"github.com/Alartist40/cynapse/internal/core"
 )
 
-// PythonNeuron wraps a Python neuron process, communicating via JSON over stdin/stdout.
+// Transport is the stdin/stdout pipe pair PythonNeuron speaks JSON-RPC
+// over: Write sends a frame to the subprocess's stdin, Read receives a
+// frame from its stdout. The real subprocess satisfies this via
+// exec.Cmd's pipes; tests substitute a fake one — see WithTransport and
+// internal/bridge/flowtest.
+type Transport = io.ReadWriteCloser
+
+// TransportFactory constructs the Transport Start should use instead of
+// launching scriptPath as a real python3 subprocess.
+type TransportFactory func() (Transport, error)
+
+// StartOption customizes a PythonNeuron's Start call.
+type StartOption func(*PythonNeuron)
+
+// WithTransport overrides the real subprocess with a caller-supplied
+// Transport factory — e.g. flowtest's record/replay stubs.
+func WithTransport(factory TransportFactory) StartOption {
+	return func(p *PythonNeuron) { p.transportFactory = factory }
+}
+
+// PythonNeuron wraps a Python neuron process, communicating via JSON-RPC 2.0
+// over stdin/stdout. A single reader goroutine demultiplexes responses to
+// their caller by id, so multiple Execute/ExecuteStream calls can be
+// in flight on the same subprocess at once.
 type PythonNeuron struct {
 	id         string
 	name       string
 	caps       []string
 	scriptPath string
-	cmd        *exec.Cmd
-	stdin      io.WriteCloser
-	stdout     *bufio.Scanner
-	mu         sync.Mutex
-	running    bool
-}
 
-// bridgeRequest is the JSON sent to the Python process.
-type bridgeRequest struct {
-	Operation string            `json:"operation"`
-	Params    map[string]string `json:"params"`
-	Payload   string            `json:"payload,omitempty"`
-}
+	transportFactory TransportFactory
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	out   frameWriter
+
+	nextID uint64
 
-// bridgeResponse is the JSON returned from the Python process.
-type bridgeResponse struct {
-	Success    bool              `json:"success"`
-	Output     string            `json:"output"`
-	Confidence float64           `json:"confidence,omitempty"`
-	Details    map[string]string `json:"details,omitempty"`
-	Error      string            `json:"error,omitempty"`
+	mu       sync.Mutex // guards running, pending, streams and stdin writes
+	running  bool
+	pending  map[uint64]chan rpcMessage
+	streams  map[uint64]chan core.Result
+	readDone chan struct{}
 }
 
 // NewPythonNeuron creates a bridge neuron backed by a Python script.
@@ -50,6 +68,8 @@ func NewPythonNeuron(id, name, scriptPath string, capabilities []string) *Python
 		name:       name,
 		caps:       capabilities,
 		scriptPath: scriptPath,
+		pending:    make(map[uint64]chan rpcMessage),
+		streams:    make(map[uint64]chan core.Result),
 	}
 }
 
@@ -57,89 +77,308 @@ func (p *PythonNeuron) ID() string             { return p.id }
 func (p *PythonNeuron) Name() string           { return p.name }
 func (p *PythonNeuron) Capabilities() []string { return p.caps }
 
-// Start launches the Python subprocess. Call this once before Execute.
-func (p *PythonNeuron) Start() error {
+// Start launches the Python subprocess and its reader goroutine. Call this
+// once before Execute or ExecuteStream. Passing WithTransport swaps out the
+// real subprocess for a caller-supplied Transport, e.g. a flowtest stub.
+func (p *PythonNeuron) Start(opts ...StartOption) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
 
-	p.cmd = exec.Command("python3", p.scriptPath, "--bridge")
-	var err error
-	p.stdin, err = p.cmd.StdinPipe()
-	if err != nil {
-		return fmt.Errorf("bridge stdin: %w", err)
+	for _, opt := range opts {
+		opt(p)
 	}
 
-	stdoutPipe, err := p.cmd.StdoutPipe()
-	if err != nil {
-		return fmt.Errorf("bridge stdout: %w", err)
-	}
-	p.stdout = bufio.NewScanner(stdoutPipe)
-	p.stdout.Buffer(make([]byte, 1<<20), 1<<20) // 1MB buffer
+	var stdout io.Reader
+	if p.transportFactory != nil {
+		transport, err := p.transportFactory()
+		if err != nil {
+			return fmt.Errorf("bridge transport: %w", err)
+		}
+		p.stdin = transport
+		p.out = frameWriter{w: transport}
+		stdout = transport
+	} else {
+		p.cmd = exec.Command("python3", p.scriptPath, "--bridge")
+		var err error
+		p.stdin, err = p.cmd.StdinPipe()
+		if err != nil {
+			return fmt.Errorf("bridge stdin: %w", err)
+		}
+		p.out = frameWriter{w: p.stdin}
 
-	if err := p.cmd.Start(); err != nil {
-		return fmt.Errorf("bridge start: %w", err)
+		stdoutPipe, err := p.cmd.StdoutPipe()
+		if err != nil {
+			return fmt.Errorf("bridge stdout: %w", err)
+		}
+
+		if err := p.cmd.Start(); err != nil {
+			return fmt.Errorf("bridge start: %w", err)
+		}
+		stdout = stdoutPipe
 	}
 
 	p.running = true
+	p.readDone = make(chan struct{})
+	go p.readLoop(bufio.NewReaderSize(stdout, 1<<20))
+
 	return nil
 }
 
-// Execute sends a task to the Python process and waits for a response.
-func (p *PythonNeuron) Execute(ctx context.Context, task core.Task) (core.Result, error) {
+// readLoop decodes framed rpcMessages from the subprocess until the pipe
+// closes, routing each to the caller waiting on its id (a response) or to
+// the streaming Result channel registered for that id (a notification).
+func (p *PythonNeuron) readLoop(r *bufio.Reader) {
+	defer close(p.readDone)
+
+	for {
+		raw, err := readFrame(r)
+		if err != nil {
+			p.abortPending(fmt.Errorf("bridge %s: read: %w", p.id, err))
+			return
+		}
+
+		var msg rpcMessage
+		if err := json.Unmarshal(raw, &msg); err != nil {
+			continue // malformed frame from the subprocess; drop it
+		}
+
+		if msg.ID != nil {
+			p.mu.Lock()
+			ch, ok := p.pending[*msg.ID]
+			p.mu.Unlock()
+			if ok {
+				ch <- msg
+			}
+			continue
+		}
+
+		p.dispatchNotification(msg)
+	}
+}
+
+// dispatchNotification handles server-initiated "progress"/"log" messages
+// by forwarding them as partial core.Result values on the stream channel
+// registered for their request_id. Requests made via plain Execute have no
+// stream registered, so their progress notifications are silently dropped.
+func (p *PythonNeuron) dispatchNotification(msg rpcMessage) {
+	if msg.Method != "progress" && msg.Method != "log" {
+		return
+	}
+
+	var params progressParams
+	if err := json.Unmarshal(msg.Params, &params); err != nil {
+		return
+	}
+
+	p.mu.Lock()
+	ch, ok := p.streams[params.RequestID]
+	p.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	chunk := params.Chunk
+	if msg.Method == "log" {
+		chunk = params.Message
+	}
+
+	// Non-blocking: dispatchNotification runs synchronously on the single
+	// readLoop goroutine for this subprocess. A blocking send here would
+	// stall readLoop itself the moment a consumer falls behind (or stops
+	// draining streamCh after ctx.Done() already returned the forwarding
+	// goroutine in ExecuteStream), which would freeze every other
+	// Execute/ExecuteStream call sharing the same subprocess. Drop the
+	// notification instead and let the final result still arrive via respCh.
+	select {
+	case ch <- core.Result{Success: true, Output: chunk}:
+	default:
+		log.Printf("[bridge %s] dropped %s notification for request %d: stream buffer full", p.id, msg.Method, params.RequestID)
+	}
+}
+
+// abortPending delivers err to every caller still waiting on a response,
+// used when the read side dies (subprocess exit, broken pipe).
+func (p *PythonNeuron) abortPending(err error) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	for id, ch := range p.pending {
+		close(ch)
+		delete(p.pending, id)
+	}
+	for id, ch := range p.streams {
+		close(ch)
+		delete(p.streams, id)
+	}
+	_ = err // surfaced to callers as "no response"; nothing else listens for it
+}
 
+// call assigns a fresh id, sends req as an "execute" request, and returns
+// the channel its response (or, on readLoop death, a closed channel) will
+// arrive on.
+func (p *PythonNeuron) call(params interface{}) (uint64, chan rpcMessage, error) {
+	p.mu.Lock()
 	if !p.running {
-		return core.Result{}, fmt.Errorf("bridge %s: not running (call Start first)", p.id)
+		p.mu.Unlock()
+		return 0, nil, fmt.Errorf("bridge %s: not running (call Start first)", p.id)
+	}
+	id := atomic.AddUint64(&p.nextID, 1)
+	ch := make(chan rpcMessage, 1)
+	p.pending[id] = ch
+	p.mu.Unlock()
+
+	req := rpcRequest{JSONRPC: rpcVersion, ID: id, Method: "execute", Params: params}
+	if err := p.writeLocked(req); err != nil {
+		p.mu.Lock()
+		delete(p.pending, id)
+		p.mu.Unlock()
+		return 0, nil, err
 	}
+	return id, ch, nil
+}
+
+func (p *PythonNeuron) writeLocked(v interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.out.write(v)
+}
+
+// cancel sends a "$/cancelRequest" notification for id so the Python side
+// can abort a long-running generation instead of leaving it running after
+// the Go caller has already given up.
+func (p *PythonNeuron) cancel(id uint64) {
+	_ = p.writeLocked(rpcNotification{
+		JSONRPC: rpcVersion,
+		Method:  "$/cancelRequest",
+		Params:  cancelParams{RequestID: id},
+	})
+}
+
+func (p *PythonNeuron) unregister(id uint64) {
+	p.mu.Lock()
+	delete(p.pending, id)
+	delete(p.streams, id)
+	p.mu.Unlock()
+}
 
-	req := bridgeRequest{
-		Operation: task.Operation,
-		Params:    task.Params,
-		Payload:   string(task.Payload),
+func toResult(task core.Task) map[string]interface{} {
+	return map[string]interface{}{
+		"operation": task.Operation,
+		"params":    task.Params,
+		"payload":   string(task.Payload),
 	}
+}
 
-	reqJSON, err := json.Marshal(req)
+// Execute sends a task to the Python process and waits for a response,
+// canceling the in-flight request on the Python side if ctx is done first.
+func (p *PythonNeuron) Execute(ctx context.Context, task core.Task) (core.Result, error) {
+	id, ch, err := p.call(toResult(task))
 	if err != nil {
-		return core.Result{}, fmt.Errorf("bridge marshal: %w", err)
+		return core.Result{}, err
 	}
+	defer p.unregister(id)
 
-	// Send request
-	if _, err := fmt.Fprintf(p.stdin, "%s\n", reqJSON); err != nil {
-		return core.Result{}, fmt.Errorf("bridge write: %w", err)
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			return core.Result{}, fmt.Errorf("bridge %s: no response", p.id)
+		}
+		return decodeTaskResult(p.id, msg)
+	case <-ctx.Done():
+		p.cancel(id)
+		return core.Result{}, ctx.Err()
 	}
+}
 
-	// Read response
-	if !p.stdout.Scan() {
-		return core.Result{}, fmt.Errorf("bridge %s: no response", p.id)
+// ExecuteStream behaves like Execute but returns a channel of incremental
+// core.Result values as the Python neuron reports "progress"/"log"
+// notifications (e.g. generated tokens, OCR progress), followed by the
+// final result. The channel is closed once the final result has been sent,
+// ctx is done, or the subprocess's read side dies.
+func (p *PythonNeuron) ExecuteStream(ctx context.Context, task core.Task) (<-chan core.Result, error) {
+	p.mu.Lock()
+	if !p.running {
+		p.mu.Unlock()
+		return nil, fmt.Errorf("bridge %s: not running (call Start first)", p.id)
 	}
+	id := atomic.AddUint64(&p.nextID, 1)
+	respCh := make(chan rpcMessage, 1)
+	p.pending[id] = respCh
+	streamCh := make(chan core.Result, 8)
+	p.streams[id] = streamCh
+	p.mu.Unlock()
 
-	var resp bridgeResponse
-	if err := json.Unmarshal(p.stdout.Bytes(), &resp); err != nil {
-		return core.Result{}, fmt.Errorf("bridge unmarshal: %w", err)
+	req := rpcRequest{JSONRPC: rpcVersion, ID: id, Method: "execute", Params: toResult(task)}
+	if err := p.writeLocked(req); err != nil {
+		p.unregister(id)
+		close(streamCh)
+		return nil, err
 	}
 
-	if resp.Error != "" {
-		return core.Result{Success: false, Output: resp.Error}, nil
+	out := make(chan core.Result, 8)
+	go func() {
+		defer close(out)
+		defer p.unregister(id)
+		for {
+			select {
+			case chunk, ok := <-streamCh:
+				if !ok {
+					return
+				}
+				out <- chunk
+			case msg, ok := <-respCh:
+				if ok {
+					if result, err := decodeTaskResult(p.id, msg); err == nil {
+						out <- result
+					} else {
+						out <- core.Result{Success: false, Output: err.Error()}
+					}
+				}
+				return
+			case <-ctx.Done():
+				p.cancel(id)
+				return
+			}
+		}
+	}()
+	return out, nil
+}
+
+func decodeTaskResult(neuronID string, msg rpcMessage) (core.Result, error) {
+	if msg.Error != nil {
+		return core.Result{}, msg.Error.asError()
 	}
 
+	var tr taskResult
+	if err := json.Unmarshal(msg.Result, &tr); err != nil {
+		return core.Result{}, fmt.Errorf("bridge %s: unmarshal: %w", neuronID, err)
+	}
+	if tr.Error != "" {
+		return core.Result{Success: false, Output: tr.Error}, nil
+	}
 	return core.Result{
-		Success:    resp.Success,
-		Output:     resp.Output,
-		Confidence: resp.Confidence,
-		Details:    resp.Details,
+		Success:    tr.Success,
+		Output:     tr.Output,
+		Confidence: tr.Confidence,
+		Details:    tr.Details,
 	}, nil
 }
 
-// Stop terminates the Python subprocess.
+// Stop terminates the Python subprocess and waits for the reader goroutine
+// to finish.
 func (p *PythonNeuron) Stop() error {
 	p.mu.Lock()
-	defer p.mu.Unlock()
-
 	if !p.running {
+		p.mu.Unlock()
 		return nil
 	}
 	p.running = false
+	p.mu.Unlock()
+
 	p.stdin.Close()
-	return p.cmd.Wait()
+	var err error
+	if p.cmd != nil {
+		err = p.cmd.Wait()
+	}
+	<-p.readDone
+	return err
 }
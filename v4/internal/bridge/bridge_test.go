@@ -0,0 +1,56 @@
+package bridge
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/Alartist40/cynapse/internal/core"
+)
+
+func notification(t *testing.T, method string, requestID uint64, chunk string) rpcMessage {
+	t.Helper()
+	params, err := json.Marshal(progressParams{RequestID: requestID, Chunk: chunk})
+	if err != nil {
+		t.Fatalf("marshal progressParams: %v", err)
+	}
+	return rpcMessage{JSONRPC: rpcVersion, Method: method, Params: params}
+}
+
+// TestDispatchNotification_StalledConsumerDoesNotBlock simulates a
+// consumer that has stopped draining a stream's buffer (e.g. ExecuteStream's
+// forwarding goroutine already returned after ctx.Done()). Before this fix,
+// dispatchNotification's blocking send would hang forever once streamCh
+// filled up, freezing readLoop — and with it every other in-flight call on
+// the same subprocess.
+func TestDispatchNotification_StalledConsumerDoesNotBlock(t *testing.T) {
+	p := NewPythonNeuron("test", "Test", "unused.py", nil)
+	const id = uint64(1)
+	streamCh := make(chan core.Result, 8)
+	p.streams[id] = streamCh
+
+	// Fill the buffer so any further send would block without the fix.
+	for i := 0; i < cap(streamCh); i++ {
+		streamCh <- core.Result{Success: true, Output: "filler"}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		p.dispatchNotification(notification(t, "progress", id, "overflow"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("dispatchNotification blocked on a full stream buffer instead of dropping the notification")
+	}
+}
+
+// TestDispatchNotification_UnknownRequestIDIsNoop covers the existing
+// early-return path: a notification for a request with no registered
+// stream (e.g. a plain Execute call) is silently dropped.
+func TestDispatchNotification_UnknownRequestIDIsNoop(t *testing.T) {
+	p := NewPythonNeuron("test", "Test", "unused.py", nil)
+	p.dispatchNotification(notification(t, "progress", 999, "ignored"))
+}
@@ -0,0 +1,85 @@
+package flowtest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Fixture is one canonical request/response pair: a RecordScenario run's
+// observation of what the real Python subprocess did for a given turn,
+// replayed verbatim by ReplayScenario.
+type Fixture struct {
+	Operation string            `yaml:"operation"`
+	Params    map[string]string `yaml:"params,omitempty"`
+	Payload   string            `yaml:"payload,omitempty"`
+	Response  FixtureResponse   `yaml:"response"`
+}
+
+// FixtureResponse mirrors the "result" field of the neuron's JSON-RPC
+// response for the "execute" method.
+type FixtureResponse struct {
+	Success    bool              `yaml:"success"`
+	Output     string            `yaml:"output"`
+	Confidence float64           `yaml:"confidence,omitempty"`
+	Details    map[string]string `yaml:"details,omitempty"`
+	Error      string            `yaml:"error,omitempty"`
+}
+
+// fixtureSet is the on-disk shape of a fixture file: one scenario's
+// fixtures, in turn order, tagged with the scenario name so ReplayScenario
+// can sanity-check it's reading the right file.
+type fixtureSet struct {
+	Scenario string    `yaml:"scenario"`
+	Fixtures []Fixture `yaml:"fixtures"`
+}
+
+// LoadFixtures reads the fixture file at path.
+func LoadFixtures(path string) ([]Fixture, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("flowtest: read fixtures: %w", err)
+	}
+	var set fixtureSet
+	if err := yaml.Unmarshal(body, &set); err != nil {
+		return nil, fmt.Errorf("flowtest: parse fixtures: %w", err)
+	}
+	return set.Fixtures, nil
+}
+
+// Recorder accumulates Fixtures observed by a teeTransport during a
+// RecordScenario run.
+type Recorder struct {
+	mu       sync.Mutex
+	fixtures []Fixture
+}
+
+// NewRecorder returns an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Add appends f to the set of fixtures recorded so far.
+func (r *Recorder) Add(f Fixture) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixtures = append(r.fixtures, f)
+}
+
+// Save writes every fixture recorded so far to path as YAML.
+func (r *Recorder) Save(path, scenario string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	body, err := yaml.Marshal(fixtureSet{Scenario: scenario, Fixtures: r.fixtures})
+	if err != nil {
+		return fmt.Errorf("flowtest: marshal fixtures: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("flowtest: create fixture dir: %w", err)
+	}
+	return os.WriteFile(path, body, 0644)
+}
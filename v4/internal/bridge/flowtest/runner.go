@@ -0,0 +1,166 @@
+package flowtest
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Alartist40/cynapse/internal/bridge"
+	"github.com/Alartist40/cynapse/internal/core"
+)
+
+// Report is the pass/fail outcome of running a Scenario, one TurnResult
+// per turn in order.
+type Report struct {
+	Scenario string
+	Turns    []TurnResult
+}
+
+// Passed reports whether every turn in the report passed.
+func (r Report) Passed() bool {
+	for _, t := range r.Turns {
+		if !t.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String renders a human-readable pass/fail summary with a diff line for
+// each failing turn, suitable for printing straight to CI output.
+func (r Report) String() string {
+	var b strings.Builder
+	status := "PASS"
+	if !r.Passed() {
+		status = "FAIL"
+	}
+	fmt.Fprintf(&b, "%s %s (%d turns)\n", status, r.Scenario, len(r.Turns))
+	for _, t := range r.Turns {
+		mark := "ok"
+		if !t.Passed {
+			mark = "FAIL"
+		}
+		fmt.Fprintf(&b, "  [%d] %s: %s\n", t.Index, t.Operation, mark)
+		if !t.Passed {
+			fmt.Fprintf(&b, "        %s\n", t.Diff)
+		}
+	}
+	return b.String()
+}
+
+// TurnResult is the outcome of running a single Turn against a neuron.
+type TurnResult struct {
+	Index     int
+	Operation string
+	Passed    bool
+	Diff      string // human-readable mismatch explanation; empty when Passed
+}
+
+// RecordScenario runs scenario against the real scriptPath subprocess,
+// writing the request/response pairs it observes to fixturePath as
+// canonical fixtures for a later ReplayScenario run. scriptPath is
+// duplicated from the caller rather than read off neuron, since
+// PythonNeuron keeps it unexported.
+func RecordScenario(ctx context.Context, neuron *bridge.PythonNeuron, scriptPath, fixturePath string, scenario Scenario) (Report, error) {
+	rec := NewRecorder()
+	if err := neuron.Start(bridge.WithTransport(RecordTransportFactory(scriptPath, rec))); err != nil {
+		return Report{}, fmt.Errorf("flowtest: start %s: %w", scenario.Name, err)
+	}
+	defer neuron.Stop()
+
+	report := runTurns(ctx, neuron, scenario)
+	if err := rec.Save(fixturePath, scenario.Name); err != nil {
+		return report, fmt.Errorf("flowtest: save fixtures: %w", err)
+	}
+	return report, nil
+}
+
+// ReplayScenario runs scenario against a stub transport that answers from
+// the fixtures at fixturePath instead of a real Python subprocess.
+func ReplayScenario(ctx context.Context, neuron *bridge.PythonNeuron, fixturePath string, scenario Scenario) (Report, error) {
+	fixtures, err := LoadFixtures(fixturePath)
+	if err != nil {
+		return Report{}, err
+	}
+	if err := neuron.Start(bridge.WithTransport(ReplayTransportFactory(fixtures))); err != nil {
+		return Report{}, fmt.Errorf("flowtest: start %s: %w", scenario.Name, err)
+	}
+	defer neuron.Stop()
+
+	return runTurns(ctx, neuron, scenario), nil
+}
+
+// runTurns drives scenario's turns against an already-started neuron,
+// checking each response against its turn's expectations.
+func runTurns(ctx context.Context, neuron *bridge.PythonNeuron, scenario Scenario) Report {
+	report := Report{Scenario: scenario.Name}
+
+	for i, turn := range scenario.Turns {
+		result := TurnResult{Index: i, Operation: turn.Operation}
+
+		task := core.Task{
+			Operation: turn.Operation,
+			Params:    turn.Params,
+			Payload:   []byte(turn.Payload),
+		}
+		res, err := neuron.Execute(ctx, task)
+		if err != nil {
+			result.Diff = fmt.Sprintf("execute: %v", err)
+			report.Turns = append(report.Turns, result)
+			continue
+		}
+
+		if diff := checkTurn(turn, res); diff != "" {
+			result.Diff = diff
+		} else {
+			result.Passed = true
+		}
+		report.Turns = append(report.Turns, result)
+	}
+
+	return report
+}
+
+// checkTurn evaluates turn's expectations against res, returning a
+// human-readable diff describing the first mismatch, or "" if res
+// satisfies every expectation turn declares.
+func checkTurn(turn Turn, res core.Result) string {
+	if turn.ExpectOutputRegex != "" {
+		re, err := regexp.Compile(turn.ExpectOutputRegex)
+		if err != nil {
+			return fmt.Sprintf("expect_output_regex %q: %v", turn.ExpectOutputRegex, err)
+		}
+
+		if turn.TopK > 0 {
+			completions := strings.Split(res.Details["completions"], "\n")
+			if len(completions) > turn.TopK {
+				completions = completions[:turn.TopK]
+			}
+			matched := false
+			for _, c := range completions {
+				if re.MatchString(c) {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return fmt.Sprintf("recall@%d: none of %d completions matched %q", turn.TopK, len(completions), turn.ExpectOutputRegex)
+			}
+		} else if !re.MatchString(res.Output) {
+			return fmt.Sprintf("output %q does not match %q", res.Output, turn.ExpectOutputRegex)
+		}
+	}
+
+	if turn.ExpectConfidence != nil && !turn.ExpectConfidence.contains(res.Confidence) {
+		return fmt.Sprintf("confidence %v outside [%v, %v]", res.Confidence, turn.ExpectConfidence.Min, turn.ExpectConfidence.Max)
+	}
+
+	for _, key := range turn.ExpectDetailsKeys {
+		if _, ok := res.Details[key]; !ok {
+			return fmt.Sprintf("details missing expected key %q", key)
+		}
+	}
+
+	return ""
+}
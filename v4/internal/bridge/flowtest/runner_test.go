@@ -0,0 +1,108 @@
+package flowtest
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Alartist40/cynapse/internal/bridge"
+	"github.com/Alartist40/cynapse/internal/core"
+	"gopkg.in/yaml.v3"
+)
+
+func writeFixtures(t *testing.T, fixtures []Fixture) string {
+	t.Helper()
+	body, err := yaml.Marshal(fixtureSet{Scenario: "test-scenario", Fixtures: fixtures})
+	if err != nil {
+		t.Fatalf("marshal fixtures: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "fixtures.yaml")
+	if err := os.WriteFile(path, body, 0644); err != nil {
+		t.Fatalf("write fixtures: %v", err)
+	}
+	return path
+}
+
+func resultWithCompletions(completions string) core.Result {
+	return core.Result{Success: true, Details: map[string]string{"completions": completions}}
+}
+
+func TestReplayScenario_Pass(t *testing.T) {
+	fixtures := []Fixture{
+		{
+			Operation: "classify",
+			Response: FixtureResponse{
+				Success:    true,
+				Output:     "this host looks malicious",
+				Confidence: 0.92,
+				Details:    map[string]string{"model": "elara-v2"},
+			},
+		},
+	}
+	path := writeFixtures(t, fixtures)
+
+	scenario := Scenario{
+		Name: "test-scenario",
+		Turns: []Turn{
+			{
+				Operation:         "classify",
+				ExpectOutputRegex: `(?i)malicious`,
+				ExpectConfidence:  &Range{Min: 0.8, Max: 1.0},
+				ExpectDetailsKeys: []string{"model"},
+			},
+		},
+	}
+
+	neuron := bridge.NewPythonNeuron("elara", "Elara", "unused.py", nil)
+	report, err := ReplayScenario(context.Background(), neuron, path, scenario)
+	if err != nil {
+		t.Fatalf("ReplayScenario: %v", err)
+	}
+	if !report.Passed() {
+		t.Fatalf("expected scenario to pass, got: %s", report)
+	}
+}
+
+func TestReplayScenario_ConfidenceOutOfRange(t *testing.T) {
+	fixtures := []Fixture{
+		{
+			Operation: "classify",
+			Response:  FixtureResponse{Success: true, Output: "benign", Confidence: 0.3},
+		},
+	}
+	path := writeFixtures(t, fixtures)
+
+	scenario := Scenario{
+		Name: "test-scenario",
+		Turns: []Turn{
+			{Operation: "classify", ExpectConfidence: &Range{Min: 0.8, Max: 1.0}},
+		},
+	}
+
+	neuron := bridge.NewPythonNeuron("elara", "Elara", "unused.py", nil)
+	report, err := ReplayScenario(context.Background(), neuron, path, scenario)
+	if err != nil {
+		t.Fatalf("ReplayScenario: %v", err)
+	}
+	if report.Passed() {
+		t.Fatal("expected scenario to fail on out-of-range confidence")
+	}
+}
+
+func TestCheckTurn_RecallAtK(t *testing.T) {
+	turn := Turn{
+		Operation:         "sample",
+		ExpectOutputRegex: `^yes$`,
+		TopK:              2,
+	}
+	res := resultWithCompletions("no\nyes\nno")
+	if diff := checkTurn(turn, res); diff != "" {
+		t.Fatalf("expected top-2 recall to pass, got diff: %s", diff)
+	}
+
+	miss := resultWithCompletions("no\nno\nyes")
+	if diff := checkTurn(turn, miss); diff == "" {
+		t.Fatal("expected top-2 recall to miss a match only in the 3rd completion")
+	}
+}
@@ -0,0 +1,63 @@
+// Package flowtest lets a YAML-described sequence of conversation turns be
+// run against a bridge.PythonNeuron without a Python interpreter present.
+// RecordScenario runs the real subprocess once and writes its
+// request/response exchanges to a fixture file; ReplayScenario later
+// drives the same scenario against a stub transport that answers from
+// those fixtures, so CI can catch regressions in Elara/Owl without
+// shelling out to python3.
+package flowtest
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scenario is one conversation flow: a named sequence of Turns run in
+// order against a single bridge.PythonNeuron.
+type Scenario struct {
+	Name  string `yaml:"name"`
+	Turns []Turn `yaml:"turns"`
+}
+
+// Turn is one request sent to the neuron and the expectations its
+// response must meet.
+type Turn struct {
+	Operation string            `yaml:"operation"`
+	Params    map[string]string `yaml:"params,omitempty"`
+	Payload   string            `yaml:"payload,omitempty"`
+
+	ExpectOutputRegex string   `yaml:"expect_output_regex,omitempty"`
+	ExpectConfidence  *Range   `yaml:"expect_confidence,omitempty"`
+	ExpectDetailsKeys []string `yaml:"expect_details_keys,omitempty"`
+
+	// TopK turns this turn into a Recall@k check: the response's
+	// "completions" Details key is treated as newline-separated sampled
+	// completions, and the turn passes if any of the first TopK matches
+	// ExpectOutputRegex, rather than requiring Output itself to match.
+	TopK int `yaml:"top_k,omitempty"`
+}
+
+// Range bounds an inclusive [Min, Max] range, used for confidence checks.
+type Range struct {
+	Min float64 `yaml:"min"`
+	Max float64 `yaml:"max"`
+}
+
+func (r *Range) contains(v float64) bool {
+	return v >= r.Min && v <= r.Max
+}
+
+// LoadScenario parses a YAML scenario file at path.
+func LoadScenario(path string) (Scenario, error) {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		return Scenario{}, fmt.Errorf("flowtest: read scenario: %w", err)
+	}
+	var s Scenario
+	if err := yaml.Unmarshal(body, &s); err != nil {
+		return Scenario{}, fmt.Errorf("flowtest: parse scenario: %w", err)
+	}
+	return s, nil
+}
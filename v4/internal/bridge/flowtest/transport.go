@@ -0,0 +1,206 @@
+package flowtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/Alartist40/cynapse/internal/bridge"
+)
+
+// execParams is the wire shape of an "execute" request's "params" field —
+// duplicated here rather than imported, since flowtest only ever needs to
+// speak the JSON-RPC wire protocol bridge.PythonNeuron uses, not reach into
+// its unexported types.
+type execParams struct {
+	Operation string            `json:"operation"`
+	Params    map[string]string `json:"params"`
+	Payload   string            `json:"payload"`
+}
+
+type rpcRequest struct {
+	ID     uint64          `json:"id"`
+	Method string          `json:"method"`
+	Params json.RawMessage `json:"params"`
+}
+
+// replayTransport fakes the Python side of the bridge protocol entirely
+// from recorded fixtures: each outgoing "execute" request is matched
+// against the next unconsumed fixture (in order) and answered with its
+// recorded response, over an in-memory pipe standing in for the
+// subprocess's stdout.
+type replayTransport struct {
+	fixtures []Fixture
+	next     int
+
+	pr *io.PipeReader
+	pw *io.PipeWriter
+}
+
+// ReplayTransportFactory builds a bridge.TransportFactory that answers
+// from fixtures instead of a real subprocess — pass it to
+// bridge.WithTransport.
+func ReplayTransportFactory(fixtures []Fixture) bridge.TransportFactory {
+	return func() (bridge.Transport, error) {
+		pr, pw := io.Pipe()
+		return &replayTransport{fixtures: fixtures, pr: pr, pw: pw}, nil
+	}
+}
+
+func (t *replayTransport) Write(p []byte) (int, error) {
+	var req rpcRequest
+	if err := json.Unmarshal(bytes.TrimRight(p, "\n"), &req); err != nil {
+		return 0, fmt.Errorf("flowtest: malformed request: %w", err)
+	}
+	if req.Method != "execute" {
+		return len(p), nil // e.g. "$/cancelRequest" — nothing to fake a reply to
+	}
+
+	var params execParams
+	if err := json.Unmarshal(req.Params, &params); err != nil {
+		return 0, fmt.Errorf("flowtest: malformed params: %w", err)
+	}
+
+	if t.next >= len(t.fixtures) {
+		return 0, fmt.Errorf("flowtest: no fixture recorded for turn %d (operation %q)", t.next, params.Operation)
+	}
+	fixture := t.fixtures[t.next]
+	if fixture.Operation != params.Operation {
+		return 0, fmt.Errorf("flowtest: turn %d expected operation %q, request was %q", t.next, fixture.Operation, params.Operation)
+	}
+	t.next++
+
+	body, err := json.Marshal(struct {
+		JSONRPC string          `json:"jsonrpc"`
+		ID      uint64          `json:"id"`
+		Result  FixtureResponse `json:"result"`
+	}{JSONRPC: "2.0", ID: req.ID, Result: fixture.Response})
+	if err != nil {
+		return 0, fmt.Errorf("flowtest: marshal response: %w", err)
+	}
+
+	if _, err := t.pw.Write(append(body, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (t *replayTransport) Read(p []byte) (int, error) { return t.pr.Read(p) }
+
+func (t *replayTransport) Close() error {
+	t.pw.Close()
+	return t.pr.Close()
+}
+
+// pendingRequest is an in-flight "execute" request a teeTransport is
+// waiting to pair with its response so it can turn the pair into a
+// Fixture.
+type pendingRequest struct {
+	id        uint64
+	operation string
+	params    map[string]string
+	payload   string
+}
+
+// teeTransport launches the real python3 subprocess exactly as
+// bridge.PythonNeuron.Start would on its own, but mirrors every
+// request/response pair it observes into a Recorder before forwarding the
+// bytes through unchanged.
+type teeTransport struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	stdout io.Reader
+	rec    *Recorder
+
+	pending []pendingRequest
+	acc     bytes.Buffer
+}
+
+// RecordTransportFactory builds a bridge.TransportFactory that runs the
+// real scriptPath subprocess and records every exchange into rec — pass
+// it to bridge.WithTransport for a RecordScenario run.
+func RecordTransportFactory(scriptPath string, rec *Recorder) bridge.TransportFactory {
+	return func() (bridge.Transport, error) {
+		cmd := exec.Command("python3", scriptPath, "--bridge")
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return nil, fmt.Errorf("flowtest: stdin: %w", err)
+		}
+		stdout, err := cmd.StdoutPipe()
+		if err != nil {
+			return nil, fmt.Errorf("flowtest: stdout: %w", err)
+		}
+		if err := cmd.Start(); err != nil {
+			return nil, fmt.Errorf("flowtest: start %s: %w", scriptPath, err)
+		}
+		return &teeTransport{cmd: cmd, stdin: stdin, stdout: stdout, rec: rec}, nil
+	}
+}
+
+func (t *teeTransport) Write(p []byte) (int, error) {
+	var req rpcRequest
+	if err := json.Unmarshal(bytes.TrimRight(p, "\n"), &req); err == nil && req.Method == "execute" {
+		var params execParams
+		if json.Unmarshal(req.Params, &params) == nil {
+			t.pending = append(t.pending, pendingRequest{
+				id: req.ID, operation: params.Operation, params: params.Params, payload: params.Payload,
+			})
+		}
+	}
+	return t.stdin.Write(p)
+}
+
+func (t *teeTransport) Read(p []byte) (int, error) {
+	n, err := t.stdout.Read(p)
+	if n > 0 {
+		t.acc.Write(p[:n])
+		for {
+			buf := t.acc.Bytes()
+			idx := bytes.IndexByte(buf, '\n')
+			if idx < 0 {
+				break
+			}
+			line := append([]byte(nil), buf[:idx]...)
+			t.acc.Next(idx + 1)
+			t.recordLine(line)
+		}
+	}
+	return n, err
+}
+
+func (t *teeTransport) recordLine(line []byte) {
+	var resp struct {
+		ID     uint64          `json:"id"`
+		Result json.RawMessage `json:"result"`
+	}
+	if err := json.Unmarshal(line, &resp); err != nil || resp.ID == 0 {
+		return // a notification, or nothing this teeTransport can pair up
+	}
+
+	idx := -1
+	for i, pr := range t.pending {
+		if pr.id == resp.ID {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	pr := t.pending[idx]
+	t.pending = append(t.pending[:idx], t.pending[idx+1:]...)
+
+	var fr FixtureResponse
+	if err := json.Unmarshal(resp.Result, &fr); err != nil {
+		return
+	}
+	t.rec.Add(Fixture{Operation: pr.operation, Params: pr.params, Payload: pr.payload, Response: fr})
+}
+
+func (t *teeTransport) Close() error {
+	err := t.stdin.Close()
+	t.cmd.Wait()
+	return err
+}
@@ -0,0 +1,132 @@
+package bridge
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// rpcVersion is the only JSON-RPC version this bridge speaks.
+const rpcVersion = "2.0"
+
+// rpcRequest is a Go-to-Python call that expects a matching rpcMessage
+// response carrying the same ID.
+type rpcRequest struct {
+	JSONRPC string      `json:"jsonrpc"`
+	ID      uint64      `json:"id"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcNotification is a one-way message — used by Go to send
+// "$/cancelRequest", and by Python to send "progress"/"log" updates.
+type rpcNotification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
+// rpcMessage is the generic shape anything arriving on stdout is decoded
+// into first; ID == nil distinguishes a notification from a response.
+type rpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      *uint64         `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+// rpcError is a protocol-level failure (bad method, malformed params) as
+// opposed to a business-level one, which travels inside taskResult.Error.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) asError() error {
+	return fmt.Errorf("bridge: rpc error %d: %s", e.Code, e.Message)
+}
+
+// taskResult is the shape of a response's "result" field for the "execute"
+// method — the JSON-RPC-wrapped equivalent of the old bare bridgeResponse.
+type taskResult struct {
+	Success    bool              `json:"success"`
+	Output     string            `json:"output"`
+	Confidence float64           `json:"confidence,omitempty"`
+	Details    map[string]string `json:"details,omitempty"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// progressParams is the "params" shape of a "progress" or "log"
+// notification: the id of the in-flight request it reports on, plus
+// whichever of chunk/message the method carries.
+type progressParams struct {
+	RequestID uint64 `json:"request_id"`
+	Chunk     string `json:"chunk,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// cancelParams is the "params" shape of a "$/cancelRequest" notification.
+type cancelParams struct {
+	RequestID uint64 `json:"request_id"`
+}
+
+// frameWriter serializes writes to the subprocess's stdin as
+// newline-delimited JSON — the simpler of the two framings this bridge
+// accepts on read, and sufficient since Go only ever sends small messages.
+type frameWriter struct {
+	w io.Writer
+}
+
+func (f *frameWriter) write(v interface{}) error {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("bridge marshal: %w", err)
+	}
+	_, err = fmt.Fprintf(f.w, "%s\n", body)
+	return err
+}
+
+// readFrame reads one message from the subprocess's stdout. It accepts
+// either framing a Python neuron might use: LSP-style
+// "Content-Length: N\r\n\r\n<N bytes>" headers, or a single line of JSON
+// terminated by '\n'. The framing is detected per-message from whether the
+// line starts with "Content-Length:", so a script can even mix the two
+// across its lifetime without the Go side caring.
+func readFrame(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimRight(line, "\r\n")
+
+	if !strings.HasPrefix(trimmed, "Content-Length:") {
+		return []byte(trimmed), nil
+	}
+
+	length, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(trimmed, "Content-Length:")))
+	if err != nil {
+		return nil, fmt.Errorf("bridge: malformed Content-Length header %q: %w", trimmed, err)
+	}
+
+	// Consume headers up to the blank line separating them from the body.
+	for {
+		header, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimRight(header, "\r\n") == "" {
+			break
+		}
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
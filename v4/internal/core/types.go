@@ -1,7 +1,15 @@
 // Package core defines shared types and interfaces for Cynapse v4.
 package core
 
-import "context"
+import (
+	"context"
+	"errors"
+)
+
+// ErrNonRetryable lets a NodeHandler short-circuit the engine's retry loop
+// by wrapping (or returning) this sentinel — e.g. for validation failures
+// that would never succeed on a later attempt.
+var ErrNonRetryable = errors.New("node: non-retryable error")
 
 // Result is the standard output from any neuron or node execution.
 type Result struct {
@@ -28,6 +36,19 @@ type Neuron interface {
 	Execute(ctx context.Context, task Task) (Result, error)
 }
 
+// StreamingNeuron is implemented by neurons that can report incremental
+// progress instead of only a final Result, e.g. a bridged Python neuron
+// forwarding generated tokens as they're produced. Engine.StreamTask
+// type-asserts for it, so implementing this is opt-in: a Neuron that only
+// satisfies the base interface still works, just without live updates.
+type StreamingNeuron interface {
+	Neuron
+	// ExecuteStream behaves like Execute but returns a channel of
+	// incremental Result values, ending with the final result, closed once
+	// the task completes, ctx is done, or the neuron dies.
+	ExecuteStream(ctx context.Context, task Task) (<-chan Result, error)
+}
+
 // Node represents a single step in a HiveMind workflow.
 type Node struct {
 	ID        string                 `json:"id" yaml:"id"`
@@ -8,11 +8,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"sync"
 	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 
+	"github.com/Alartist40/cynapse/internal/alerts"
 	"github.com/Alartist40/cynapse/internal/core"
 	"github.com/Alartist40/cynapse/internal/core/validator"
 	"github.com/Alartist40/cynapse/internal/techsupport"
@@ -27,6 +29,23 @@ type Config struct {
 	WorkflowPath      string `json:"workflow_path" yaml:"workflow_path"`
 	SandboxEnabled    bool   `json:"sandbox_enabled" yaml:"sandbox_enabled"`
 	AutoApprove       bool   `json:"auto_approve" yaml:"auto_approve"`
+
+	// DefaultRetry applies to nodes that don't set their own Config["retry"]
+	// block. Nil falls back to DefaultRetryPolicy() (single attempt).
+	DefaultRetry *RetryPolicy `json:"default_retry,omitempty" yaml:"default_retry,omitempty"`
+
+	// ResumeOnStartup controls what happens to instances found Running or
+	// Paused when the engine starts (e.g. after a crash): resume them from
+	// their last completed node if true, or mark them Cancelled if false.
+	ResumeOnStartup bool `json:"resume_on_startup" yaml:"resume_on_startup"`
+
+	// ExtensionEndpoints are HTTP(S) base URLs queried at startup for a
+	// signed manifest of node types the endpoint can run; see extensions.go.
+	ExtensionEndpoints []string `json:"extension_endpoints,omitempty" yaml:"extension_endpoints,omitempty"`
+	// ExtensionPubKey is the hex-encoded Ed25519 public key manifests must
+	// be signed with. When it is unset, manifests are registered unverified
+	// and a warning is logged.
+	ExtensionPubKey string `json:"extension_pubkey,omitempty" yaml:"extension_pubkey,omitempty"`
 }
 
 // DefaultConfig returns sensible defaults.
@@ -87,8 +106,13 @@ type Engine struct {
 	itMode    *techsupport.Executor
 	validator *validator.Validator
 	bus       *EventBus
+	alerts    *alerts.Bus
 	running   map[string]context.CancelFunc
 	mu        sync.RWMutex
+
+	// handlerSource maps a registered node type to where it came from: a
+	// literal "builtin", or the extension endpoint URL that supplied it.
+	handlerSource map[string]string
 }
 
 // New creates a new HiveMind engine.
@@ -100,14 +124,16 @@ func New(cfg Config) (*Engine, error) {
 
 	reg := techsupport.NewRegistry("./data/techsupport")
 	e := &Engine{
-		config:    cfg,
-		db:        db,
-		handlers:  make(map[string]core.NodeHandler),
-		neurons:   make(map[string]core.Neuron),
-		itMode:    techsupport.NewExecutor(reg),
-		validator: validator.New(),
-		bus:       &EventBus{},
-		running:   make(map[string]context.CancelFunc),
+		config:        cfg,
+		db:            db,
+		handlers:      make(map[string]core.NodeHandler),
+		neurons:       make(map[string]core.Neuron),
+		itMode:        techsupport.NewExecutor(reg),
+		validator:     validator.New(),
+		bus:           &EventBus{},
+		alerts:        alerts.NewBus(),
+		running:       make(map[string]context.CancelFunc),
+		handlerSource: make(map[string]string),
 	}
 
 	if err := e.initDB(); err != nil {
@@ -115,6 +141,8 @@ func New(cfg Config) (*Engine, error) {
 	}
 
 	e.registerDefaultHandlers()
+	e.loadExtensions()
+	e.recoverInstances()
 	return e, nil
 }
 
@@ -123,11 +151,27 @@ func (e *Engine) Bus() *EventBus {
 	return e.bus
 }
 
-// RegisterNeuron adds a neuron to the engine.
+// Alerts returns the alert bus so callers can register sinks (stdout/JSONL,
+// webhook, Matrix, ...) that every alert-aware neuron's events fan out to.
+func (e *Engine) Alerts() *alerts.Bus {
+	return e.alerts
+}
+
+// RegisterAlertSink adds sink to the engine's alert bus.
+func (e *Engine) RegisterAlertSink(sink alerts.Sink) {
+	e.alerts.Register(sink)
+}
+
+// RegisterNeuron adds a neuron to the engine. If the neuron implements
+// alerts.Emitter, it's wired up to the engine's alert bus so it can page a
+// human without the engine needing to know anything about that neuron.
 func (e *Engine) RegisterNeuron(n core.Neuron) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.neurons[n.ID()] = n
+	if emitter, ok := n.(alerts.Emitter); ok {
+		emitter.SetAlertSink(e.alerts)
+	}
 }
 
 // RegisterHandler adds a node handler.
@@ -166,10 +210,121 @@ func (e *Engine) ExecuteTask(ctx context.Context, task core.Task) (core.Result,
 	return neuron.Execute(ctx, task)
 }
 
-// Execute runs a workflow, dispatching nodes concurrently where possible.
+// StreamTask behaves like ExecuteTask but returns a channel of incremental
+// Result values instead of blocking for the whole task. Neurons that
+// implement core.StreamingNeuron stream real progress; every other neuron
+// is emulated with a single-chunk channel fed by its ordinary Execute, so
+// callers (e.g. the TUI) don't need to know which kind they got.
+func (e *Engine) StreamTask(ctx context.Context, task core.Task) (<-chan core.Result, error) {
+	e.mu.RLock()
+	neuron, ok := e.neurons[task.NeuronID]
+	e.mu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("neuron %s not found", task.NeuronID)
+	}
+
+	if sn, ok := neuron.(core.StreamingNeuron); ok {
+		return sn.ExecuteStream(ctx, task)
+	}
+
+	out := make(chan core.Result, 1)
+	go func() {
+		defer close(out)
+		res, err := neuron.Execute(ctx, task)
+		if err != nil {
+			res = core.Result{Success: false, Output: err.Error()}
+		}
+		out <- res
+	}()
+	return out, nil
+}
+
+// Execute runs a workflow as a dependency DAG: a node becomes runnable once
+// every upstream node referenced by its Inputs (of the form
+// "<nodeID>.<field>") has completed, and runnable nodes are dispatched
+// concurrently on a pool bounded by Config.MaxConcurrentBees. Node.Condition
+// can skip a branch based on prior outputs, and Node.Next explicitly queues
+// successors so authors can express fan-out (and loop-back edges) that
+// Inputs references alone can't reach. Each node still runs at most once per
+// instance — a Next edge pointing back at an already-completed node is a
+// no-op rather than an infinite loop. Any node error cancels the whole
+// instance (fail-fast).
 func (e *Engine) Execute(ctx context.Context, wf core.Workflow, initialInputs map[string]interface{}) (core.Result, error) {
-	ctx, cancel := context.WithCancel(ctx)
+	if err := validateWorkflow(wf); err != nil {
+		return core.Result{}, err
+	}
 	instanceID := fmt.Sprintf("%s_%d", wf.ID, time.Now().UnixMilli())
+	if err := e.createInstanceRow(instanceID, wf.ID); err != nil {
+		return core.Result{}, fmt.Errorf("persist instance: %w", err)
+	}
+	return e.runInstance(ctx, instanceID, wf, initialInputs)
+}
+
+// validateWorkflow rejects a workflow before it can ever hang a DAG run. The
+// scheduler only ever reaches a node by seeding it directly (no Inputs deps
+// and not targeted by any Next edge) or by walking Next edges out from a
+// seed; a node whose Inputs reference another node's output without a Next
+// edge wiring it in is never scheduled, so its dependents block on a doneCh
+// that nothing ever closes and runInstance hangs forever. This walks the
+// same reachability the scheduler uses and fails fast on anything it would
+// never reach, plus any Inputs/Next reference to a node ID that doesn't
+// exist at all.
+func validateWorkflow(wf core.Workflow) error {
+	nodesByID := make(map[string]core.Node, len(wf.Nodes))
+	for _, n := range wf.Nodes {
+		nodesByID[n.ID] = n
+	}
+
+	referencedAsNext := make(map[string]bool)
+	for _, n := range wf.Nodes {
+		for _, next := range n.Next {
+			if _, ok := nodesByID[next]; !ok {
+				return fmt.Errorf("workflow %s: node %s has Next edge to unknown node %s", wf.ID, n.ID, next)
+			}
+			referencedAsNext[next] = true
+		}
+	}
+
+	reachable := make(map[string]bool)
+	var visit func(id string)
+	visit = func(id string) {
+		if reachable[id] {
+			return
+		}
+		reachable[id] = true
+		for _, next := range nodesByID[id].Next {
+			visit(next)
+		}
+	}
+	for _, n := range wf.Nodes {
+		if len(inputDepNodeIDs(n)) == 0 && !referencedAsNext[n.ID] {
+			visit(n.ID)
+		}
+	}
+
+	for _, n := range wf.Nodes {
+		if !reachable[n.ID] {
+			return fmt.Errorf("workflow %s: node %s is never reachable from a seed node (no Next edge leads to it)", wf.ID, n.ID)
+		}
+		for _, dep := range inputDepNodeIDs(n) {
+			if _, ok := nodesByID[dep]; !ok {
+				return fmt.Errorf("workflow %s: node %s has Inputs referencing unknown node %s", wf.ID, n.ID, dep)
+			}
+			if !reachable[dep] {
+				return fmt.Errorf("workflow %s: node %s depends on %s, which is never scheduled (add a Next edge reaching it)", wf.ID, n.ID, dep)
+			}
+		}
+	}
+	return nil
+}
+
+// runInstance drives instanceID's DAG to completion, persisting each state
+// transition to SQLite with optimistic concurrency so a crashed process can
+// resume (or cleanly cancel) it on restart. It's shared by fresh Execute
+// calls and by recoverInstances resuming a previously running instance.
+func (e *Engine) runInstance(ctx context.Context, instanceID string, wf core.Workflow, initialInputs map[string]interface{}) (core.Result, error) {
+	ctx, cancel := context.WithCancel(ctx)
 
 	e.mu.Lock()
 	e.running[instanceID] = cancel
@@ -182,69 +337,241 @@ func (e *Engine) Execute(ctx context.Context, wf core.Workflow, initialInputs ma
 		cancel()
 	}()
 
+	if err := e.transitionInstance(instanceID, func(row *instanceRow) (bool, error) {
+		if row.state == core.BeeStateCompleted || row.state == core.BeeStateFailed || row.state == core.BeeStateCancelled {
+			return false, nil
+		}
+		row.state = core.BeeStateRunning
+		return true, nil
+	}); err != nil {
+		return core.Result{}, fmt.Errorf("persist instance: %w", err)
+	}
+
 	e.bus.Publish(Event{Type: "bee_started", BeeID: instanceID})
 
 	results := &sync.Map{}
-	// Load initial inputs
 	for k, v := range initialInputs {
 		results.Store(k, v)
 	}
 
-	var executeErr error
+	nodesByID := make(map[string]core.Node, len(wf.Nodes))
+	for _, n := range wf.Nodes {
+		nodesByID[n.ID] = n
+	}
 
-	for _, node := range wf.Nodes {
-		select {
-		case <-ctx.Done():
-			return core.Result{}, ctx.Err()
-		default:
-		}
+	limit := e.config.MaxConcurrentBees
+	if limit <= 0 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		queued   = make(map[string]bool)
+		doneCh   = make(map[string]chan struct{})
+		firstErr error
+	)
 
-		e.bus.Publish(Event{Type: "node_started", BeeID: instanceID, NodeID: node.ID})
+	getDoneCh := func(id string) chan struct{} {
+		mu.Lock()
+		defer mu.Unlock()
+		ch, ok := doneCh[id]
+		if !ok {
+			ch = make(chan struct{})
+			doneCh[id] = ch
+		}
+		return ch
+	}
 
-		// Gather inputs from previous node outputs
-		inputs := make(map[string]interface{})
-		for key, ref := range node.Inputs {
-			if val, ok := results.Load(ref); ok {
-				inputs[key] = val
-			}
+	setErr := func(err error) {
+		mu.Lock()
+		if firstErr == nil {
+			firstErr = err
 		}
+		mu.Unlock()
+		cancel()
+	}
 
-		// Get handler
-		e.mu.RLock()
-		handler, ok := e.handlers[node.Type]
-		e.mu.RUnlock()
+	// On resume, initialInputs already carries "<nodeID>.<field>" results
+	// persisted from a prior run; treat those nodes as already completed
+	// rather than re-running their handlers.
+	for id := range nodesByID {
+		if hasAnyResultFor(results, id) {
+			queued[id] = true
+			close(getDoneCh(id))
+		}
+	}
 
-		if !ok {
-			executeErr = fmt.Errorf("unknown node type: %s", node.Type)
-			e.bus.Publish(Event{Type: "node_failed", BeeID: instanceID, NodeID: node.ID, Payload: executeErr.Error()})
-			break
+	var schedule func(id string)
+	schedule = func(id string) {
+		mu.Lock()
+		if queued[id] {
+			mu.Unlock()
+			return
 		}
+		queued[id] = true
+		mu.Unlock()
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(getDoneCh(id))
+
+			node, ok := nodesByID[id]
+			if !ok {
+				setErr(fmt.Errorf("unknown node: %s", id))
+				return
+			}
+
+			for _, dep := range inputDepNodeIDs(node) {
+				select {
+				case <-getDoneCh(dep):
+				case <-ctx.Done():
+					return
+				}
+			}
 
-		// Execute node with timeout
-		nodeCtx, nodeCancel := context.WithTimeout(ctx, 30*time.Second)
-		output, err := handler.Execute(nodeCtx, inputs, node.Config)
-		nodeCancel()
+			select {
+			case <-ctx.Done():
+				return
+			default:
+			}
 
-		if err != nil {
-			executeErr = fmt.Errorf("node %s failed: %w", node.ID, err)
-			e.bus.Publish(Event{Type: "node_failed", BeeID: instanceID, NodeID: node.ID, Payload: err.Error()})
-			break
-		}
+			if node.Condition != "" && !evalCondition(node.Condition, results) {
+				for _, next := range node.Next {
+					schedule(next)
+				}
+				return
+			}
+
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-sem }()
+
+			e.mu.RLock()
+			handler, ok := e.handlers[node.Type]
+			e.mu.RUnlock()
+			if !ok {
+				setErr(fmt.Errorf("unknown node type: %s", node.Type))
+				return
+			}
+
+			e.bus.Publish(Event{Type: "node_started", BeeID: instanceID, NodeID: id})
+
+			inputs := make(map[string]interface{})
+			for key, ref := range node.Inputs {
+				if val, ok := results.Load(ref); ok {
+					inputs[key] = val
+				}
+			}
+
+			policy := e.retryPolicyForNode(node)
+			maxAttempts := policy.MaxAttempts
+			if maxAttempts <= 0 {
+				maxAttempts = 1
+			}
+
+			var output map[string]interface{}
+			var err error
+			for attempt := 1; attempt <= maxAttempts; attempt++ {
+				nodeCtx, nodeCancel := context.WithTimeout(ctx, policy.attemptTimeout())
+				output, err = handler.Execute(nodeCtx, inputs, node.Config)
+				nodeCancel()
+
+				if err == nil || !policy.shouldRetry(err, attempt) {
+					break
+				}
+
+				delay := policy.backoffDelay(attempt - 1)
+				e.bus.Publish(Event{
+					Type:   "node_retry",
+					BeeID:  instanceID,
+					NodeID: id,
+					Payload: map[string]interface{}{
+						"attempt": attempt,
+						"delay_s": delay.Seconds(),
+						"error":   err.Error(),
+					},
+				})
+
+				select {
+				case <-time.After(delay):
+				case <-ctx.Done():
+					return
+				}
+			}
 
-		// Store outputs
-		for k, v := range output {
-			results.Store(fmt.Sprintf("%s.%s", node.ID, k), v)
+			if err != nil {
+				e.bus.Publish(Event{Type: "node_failed", BeeID: instanceID, NodeID: id, Payload: err.Error()})
+				setErr(fmt.Errorf("node %s failed: %w", id, err))
+				return
+			}
+
+			for k, v := range output {
+				results.Store(fmt.Sprintf("%s.%s", id, k), v)
+			}
+
+			if err := e.transitionInstance(instanceID, func(row *instanceRow) (bool, error) {
+				if row.state != core.BeeStateRunning {
+					return false, nil
+				}
+				if row.context == nil {
+					row.context = make(map[string]interface{})
+				}
+				for k, v := range output {
+					row.context[fmt.Sprintf("%s.%s", id, k)] = v
+				}
+				row.currentNode = id
+				return true, nil
+			}); err != nil {
+				log.Printf("[HiveMind] persist node %s progress: %v", id, err)
+			}
+
+			e.bus.Publish(Event{Type: "node_completed", BeeID: instanceID, NodeID: id})
+
+			for _, next := range node.Next {
+				schedule(next)
+			}
+		}()
+	}
+
+	// Seed nodes that have no data dependencies and aren't only reachable via
+	// another node's Next — those start immediately; the rest are pulled in
+	// as their dependencies (or an upstream Next edge) resolve.
+	referencedAsNext := make(map[string]bool)
+	for _, n := range wf.Nodes {
+		for _, next := range n.Next {
+			referencedAsNext[next] = true
 		}
+	}
+	for _, n := range wf.Nodes {
+		if len(inputDepNodeIDs(n)) == 0 && !referencedAsNext[n.ID] {
+			schedule(n.ID)
+		}
+	}
+
+	wg.Wait()
 
-		e.bus.Publish(Event{Type: "node_completed", BeeID: instanceID, NodeID: node.ID})
+	if firstErr != nil {
+		e.bus.Publish(Event{Type: "bee_failed", BeeID: instanceID, Payload: firstErr.Error()})
+		e.finalizeInstance(instanceID, core.BeeStateFailed)
+		return core.Result{Success: false, Output: firstErr.Error()}, firstErr
 	}
 
-	if executeErr != nil {
-		e.bus.Publish(Event{Type: "bee_failed", BeeID: instanceID, Payload: executeErr.Error()})
-		return core.Result{Success: false, Output: executeErr.Error()}, executeErr
+	select {
+	case <-ctx.Done():
+		// Cancelled mid-flight (Kill/Pause) rather than a node failure.
+		e.finalizeInstance(instanceID, core.BeeStateCancelled)
+		return core.Result{Success: false, Output: "cancelled"}, ctx.Err()
+	default:
 	}
 
 	e.bus.Publish(Event{Type: "bee_completed", BeeID: instanceID})
+	e.finalizeInstance(instanceID, core.BeeStateCompleted)
 
 	// Try to extract final output
 	var finalOutput string
@@ -257,15 +584,155 @@ func (e *Engine) Execute(ctx context.Context, wf core.Workflow, initialInputs ma
 	return core.Result{Success: true, Output: finalOutput}, nil
 }
 
-// Kill cancels a running workflow instance.
+// finalizeInstance persists the instance's terminal state, unless it has
+// already been marked Paused (by a racing Pause call, which should survive
+// this goroutine's exit) or is already terminal.
+func (e *Engine) finalizeInstance(instanceID string, final core.BeeState) {
+	if err := e.transitionInstance(instanceID, func(row *instanceRow) (bool, error) {
+		if row.state == core.BeeStatePaused || row.state == core.BeeStateCompleted ||
+			row.state == core.BeeStateFailed || row.state == core.BeeStateCancelled {
+			return false, nil
+		}
+		row.state = final
+		return true, nil
+	}); err != nil {
+		log.Printf("[HiveMind] finalize instance %s: %v", instanceID, err)
+	}
+}
+
+// inputDepNodeIDs extracts the distinct upstream node IDs referenced by
+// node.Inputs values of the form "<nodeID>.<field>".
+func inputDepNodeIDs(node core.Node) []string {
+	seen := make(map[string]bool)
+	var deps []string
+	for _, ref := range node.Inputs {
+		idx := strings.Index(ref, ".")
+		if idx <= 0 {
+			continue
+		}
+		nodeID := ref[:idx]
+		if !seen[nodeID] {
+			seen[nodeID] = true
+			deps = append(deps, nodeID)
+		}
+	}
+	return deps
+}
+
+// evalCondition evaluates a minimal "<nodeID>.<field> [==|!=] value"
+// expression against prior node outputs. A bare reference with no operator
+// is a truthiness check: the branch is skipped when the referenced output is
+// empty, "false", or missing.
+func evalCondition(expr string, results *sync.Map) bool {
+	expr = strings.TrimSpace(expr)
+	for _, op := range []string{"==", "!="} {
+		idx := strings.Index(expr, op)
+		if idx <= 0 {
+			continue
+		}
+		ref := strings.TrimSpace(expr[:idx])
+		want := strings.Trim(strings.TrimSpace(expr[idx+len(op):]), `"'`)
+		got := fmt.Sprintf("%v", loadResult(results, ref))
+		if op == "==" {
+			return got == want
+		}
+		return got != want
+	}
+	val := fmt.Sprintf("%v", loadResult(results, expr))
+	return val != "" && val != "false" && val != "<nil>"
+}
+
+func loadResult(results *sync.Map, ref string) interface{} {
+	v, _ := results.Load(ref)
+	return v
+}
+
+// hasAnyResultFor reports whether results already holds an output
+// (nodeID.field) for nodeID, meaning that node has already run — used to
+// skip re-executing nodes when resuming a persisted instance.
+func hasAnyResultFor(results *sync.Map, nodeID string) bool {
+	prefix := nodeID + "."
+	found := false
+	results.Range(func(k, _ interface{}) bool {
+		if ks, ok := k.(string); ok && strings.HasPrefix(ks, prefix) {
+			found = true
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// Kill cancels a running workflow instance and marks it Cancelled in the DB
+// (a no-op if it has already reached a terminal state).
 func (e *Engine) Kill(instanceID string) {
 	e.mu.RLock()
 	cancel, ok := e.running[instanceID]
 	e.mu.RUnlock()
 	if ok {
 		cancel()
-		log.Printf("[HiveMind] Cancelled %s", instanceID)
 	}
+
+	err := e.transitionInstance(instanceID, func(row *instanceRow) (bool, error) {
+		if row.state == core.BeeStateCompleted || row.state == core.BeeStateFailed || row.state == core.BeeStateCancelled {
+			return false, nil
+		}
+		row.state = core.BeeStateCancelled
+		return true, nil
+	})
+	if err != nil {
+		log.Printf("[HiveMind] Kill %s: %v", instanceID, err)
+		return
+	}
+	log.Printf("[HiveMind] Cancelled %s", instanceID)
+}
+
+// Pause marks a running instance Paused and cancels its context, which
+// stops dispatch of new nodes and also cancels node calls already in
+// flight; a node cut short this way has no persisted output and runs again
+// when Resume picks the instance back up, even across a restart.
+func (e *Engine) Pause(instanceID string) error {
+	e.mu.RLock()
+	cancel, ok := e.running[instanceID]
+	e.mu.RUnlock()
+
+	err := e.transitionInstance(instanceID, func(row *instanceRow) (bool, error) {
+		if row.state != core.BeeStateRunning {
+			return false, fmt.Errorf("instance %s is %s, not running", instanceID, row.state)
+		}
+		row.state = core.BeeStatePaused
+		return true, nil
+	})
+	if err != nil {
+		return err
+	}
+	if ok {
+		cancel()
+	}
+	return nil
+}
+
+// Resume relaunches a Paused (or Running-but-orphaned, e.g. after a crash)
+// instance from its last persisted node outputs.
+func (e *Engine) Resume(ctx context.Context, instanceID string) (core.Result, error) {
+	row, err := e.readInstanceRow(instanceID)
+	if err != nil {
+		return core.Result{}, fmt.Errorf("read instance %s: %w", instanceID, err)
+	}
+	if row.state != core.BeeStatePaused && row.state != core.BeeStateRunning {
+		return core.Result{}, fmt.Errorf("instance %s is %s, not resumable", instanceID, row.state)
+	}
+
+	workflowID, err := e.instanceWorkflowID(instanceID)
+	if err != nil {
+		return core.Result{}, err
+	}
+	wf, err := e.LoadWorkflow(workflowID)
+	if err != nil {
+		return core.Result{}, fmt.Errorf("load workflow %s: %w", workflowID, err)
+	}
+
+	return e.runInstance(ctx, instanceID, *wf, row.context)
 }
 
 // Close shuts down the engine gracefully.
@@ -289,7 +756,8 @@ func (e *Engine) initDB() error {
 		current_node TEXT,
 		start_time REAL,
 		end_time REAL,
-		logs TEXT
+		logs TEXT,
+		revision INTEGER NOT NULL DEFAULT 0
 	);
 	CREATE TABLE IF NOT EXISTS memory (
 		id INTEGER PRIMARY KEY AUTOINCREMENT,
@@ -299,12 +767,26 @@ func (e *Engine) initDB() error {
 		timestamp REAL,
 		workflow_id TEXT
 	);`
-	_, err := e.db.Exec(schema)
-	return err
+	if _, err := e.db.Exec(schema); err != nil {
+		return err
+	}
+
+	// Older databases predate the revision column; add it if missing. SQLite
+	// has no "ADD COLUMN IF NOT EXISTS", so just ignore the duplicate-column
+	// error from a second run against an already-migrated DB.
+	if _, err := e.db.Exec(`ALTER TABLE instances ADD COLUMN revision INTEGER NOT NULL DEFAULT 0`); err != nil &&
+		!strings.Contains(err.Error(), "duplicate column") {
+		return err
+	}
+
+	return nil
 }
 
 // SaveWorkflow persists a workflow definition.
 func (e *Engine) SaveWorkflow(wf core.Workflow) error {
+	if err := validateWorkflow(wf); err != nil {
+		return err
+	}
 	data, err := json.Marshal(wf)
 	if err != nil {
 		return err
@@ -336,5 +818,31 @@ func (e *Engine) registerDefaultHandlers() {
 	e.handlers["text_chunker"] = &TextChunkerHandler{}
 	e.handlers["neuron"] = &NeuronHandler{engine: e}
 	e.handlers["llm"] = &LLMHandler{}
-	e.handlers["agent"] = &AgentHandler{}
+
+	for nodeType := range e.handlers {
+		e.handlerSource[nodeType] = "builtin"
+	}
+}
+
+// HandlerInfo describes a registered node handler and where it came from.
+type HandlerInfo struct {
+	NodeType string `json:"node_type"`
+	Source   string `json:"source"` // "builtin" or an extension endpoint URL
+}
+
+// ListHandlers returns every registered node type and its source, so the
+// TUI can show which types are built-in vs loaded from an extension.
+func (e *Engine) ListHandlers() []HandlerInfo {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	infos := make([]HandlerInfo, 0, len(e.handlers))
+	for nodeType := range e.handlers {
+		source := e.handlerSource[nodeType]
+		if source == "" {
+			source = "builtin"
+		}
+		infos = append(infos, HandlerInfo{NodeType: nodeType, Source: source})
+	}
+	return infos
 }
@@ -0,0 +1,76 @@
+package hivemind
+
+import (
+	"testing"
+
+	"github.com/Alartist40/cynapse/internal/core"
+)
+
+func TestValidateWorkflow_LinearOK(t *testing.T) {
+	wf := core.Workflow{
+		ID: "wf1",
+		Nodes: []core.Node{
+			{ID: "a", Next: []string{"b"}},
+			{ID: "b", Inputs: map[string]string{"in": "a.out"}},
+		},
+	}
+	if err := validateWorkflow(wf); err != nil {
+		t.Fatalf("expected valid workflow, got: %v", err)
+	}
+}
+
+func TestValidateWorkflow_UnreachableInputDep(t *testing.T) {
+	// "b" depends on "a"'s output but nothing seeds or reaches "a" via a
+	// Next edge (a typo'd or missing wiring) -- the scheduler would never
+	// run "a", so "b" would block on a doneCh that's never closed.
+	wf := core.Workflow{
+		ID: "wf2",
+		Nodes: []core.Node{
+			{ID: "a", Inputs: map[string]string{"in": "seed.out"}},
+			{ID: "b", Inputs: map[string]string{"in": "a.out"}},
+		},
+	}
+	if err := validateWorkflow(wf); err == nil {
+		t.Fatal("expected error for node unreachable via any seed or Next edge")
+	}
+}
+
+func TestValidateWorkflow_UnknownInputDep(t *testing.T) {
+	wf := core.Workflow{
+		ID: "wf3",
+		Nodes: []core.Node{
+			{ID: "a", Inputs: map[string]string{"in": "typo.out"}},
+		},
+	}
+	if err := validateWorkflow(wf); err == nil {
+		t.Fatal("expected error for Inputs referencing an unknown node")
+	}
+}
+
+func TestValidateWorkflow_UnknownNextEdge(t *testing.T) {
+	wf := core.Workflow{
+		ID: "wf4",
+		Nodes: []core.Node{
+			{ID: "a", Next: []string{"missing"}},
+		},
+	}
+	if err := validateWorkflow(wf); err == nil {
+		t.Fatal("expected error for Next edge to an unknown node")
+	}
+}
+
+func TestValidateWorkflow_ConditionalBranchReachableViaNext(t *testing.T) {
+	// A node with data deps is fine as long as a Next edge from a seed
+	// eventually reaches it, even through an intermediate branch node.
+	wf := core.Workflow{
+		ID: "wf5",
+		Nodes: []core.Node{
+			{ID: "seed", Next: []string{"branch"}},
+			{ID: "branch", Next: []string{"leaf"}},
+			{ID: "leaf", Inputs: map[string]string{"in": "seed.out"}},
+		},
+	}
+	if err := validateWorkflow(wf); err != nil {
+		t.Fatalf("expected valid workflow, got: %v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package hivemind
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// pluginManifest is what an extension endpoint serves at GET /manifest: the
+// node types it wants to register, signed over their sorted, comma-joined
+// form with the engine's configured Config.ExtensionPubKey.
+type pluginManifest struct {
+	NodeTypes []string `json:"node_types"`
+	Signature string   `json:"signature"`
+}
+
+// loadExtensions fetches and registers handlers from every endpoint in
+// Config.ExtensionEndpoints. It never registers a node type that would
+// shadow an already-registered handler, and if Config.ExtensionPubKey is
+// set, it refuses any manifest whose signature doesn't verify. Failures are
+// logged and skipped rather than treated as fatal — a misbehaving extension
+// shouldn't keep the engine from starting.
+func (e *Engine) loadExtensions() {
+	if len(e.config.ExtensionEndpoints) == 0 {
+		return
+	}
+
+	var pubKey ed25519.PublicKey
+	if e.config.ExtensionPubKey != "" {
+		raw, err := hex.DecodeString(e.config.ExtensionPubKey)
+		if err != nil || len(raw) != ed25519.PublicKeySize {
+			log.Printf("[HiveMind] extensions: invalid ExtensionPubKey, refusing to load any endpoint")
+			return
+		}
+		pubKey = ed25519.PublicKey(raw)
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	for _, endpoint := range e.config.ExtensionEndpoints {
+		manifest, err := fetchManifest(client, endpoint)
+		if err != nil {
+			log.Printf("[HiveMind] extensions: %s: %v", endpoint, err)
+			continue
+		}
+
+		if pubKey != nil {
+			if !verifyManifest(pubKey, manifest) {
+				log.Printf("[HiveMind] extensions: %s: manifest signature invalid, skipping", endpoint)
+				continue
+			}
+		} else {
+			log.Printf("[HiveMind] extensions: %s: no ExtensionPubKey configured, registering unverified (not recommended)", endpoint)
+		}
+
+		for _, nodeType := range manifest.NodeTypes {
+			e.mu.Lock()
+			_, exists := e.handlers[nodeType]
+			if exists {
+				e.mu.Unlock()
+				log.Printf("[HiveMind] extensions: %s: refusing to hijack existing node type %q", endpoint, nodeType)
+				continue
+			}
+			e.handlers[nodeType] = NewHTTPHandler(client, endpoint, nodeType)
+			e.handlerSource[nodeType] = endpoint
+			e.mu.Unlock()
+			log.Printf("[HiveMind] extensions: registered node type %q from %s", nodeType, endpoint)
+		}
+	}
+}
+
+func fetchManifest(client *http.Client, endpoint string) (pluginManifest, error) {
+	resp, err := client.Get(strings.TrimRight(endpoint, "/") + "/manifest")
+	if err != nil {
+		return pluginManifest{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return pluginManifest{}, fmt.Errorf("manifest request: status %d", resp.StatusCode)
+	}
+	var m pluginManifest
+	if err := json.NewDecoder(resp.Body).Decode(&m); err != nil {
+		return pluginManifest{}, fmt.Errorf("decode manifest: %w", err)
+	}
+	return m, nil
+}
+
+func verifyManifest(pubKey ed25519.PublicKey, m pluginManifest) bool {
+	sig, err := hex.DecodeString(m.Signature)
+	if err != nil {
+		return false
+	}
+	return ed25519.Verify(pubKey, []byte(manifestPayload(m.NodeTypes)), sig)
+}
+
+// manifestPayload is the exact byte string an extension's private key must
+// sign: its node types, sorted, comma-joined. Sorting makes the signature
+// independent of the order an extension happens to list them in.
+func manifestPayload(nodeTypes []string) string {
+	sorted := append([]string(nil), nodeTypes...)
+	sort.Strings(sorted)
+	return strings.Join(sorted, ",")
+}
+
+// HTTPHandler implements core.NodeHandler by POSTing the node's inputs and
+// config to an extension endpoint's /execute route as JSON and decoding its
+// JSON response. It's the transport loadExtensions wires up for every node
+// type an extension manifest advertises.
+type HTTPHandler struct {
+	client   *http.Client
+	endpoint string
+	nodeType string
+}
+
+// NewHTTPHandler creates a handler that delegates nodeType execution to
+// endpoint over HTTP.
+func NewHTTPHandler(client *http.Client, endpoint, nodeType string) *HTTPHandler {
+	return &HTTPHandler{client: client, endpoint: endpoint, nodeType: nodeType}
+}
+
+type httpHandlerRequest struct {
+	NodeType string                 `json:"node_type"`
+	Inputs   map[string]interface{} `json:"inputs"`
+	Config   map[string]interface{} `json:"config"`
+}
+
+type httpHandlerResponse struct {
+	Output map[string]interface{} `json:"output"`
+	Error  string                 `json:"error,omitempty"`
+}
+
+// Execute implements core.NodeHandler.
+func (h *HTTPHandler) Execute(ctx context.Context, inputs map[string]interface{}, config map[string]interface{}) (map[string]interface{}, error) {
+	body, err := json.Marshal(httpHandlerRequest{NodeType: h.nodeType, Inputs: inputs, Config: config})
+	if err != nil {
+		return nil, fmt.Errorf("extension request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(h.endpoint, "/")+"/execute", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("extension %s: %w", h.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	var out httpHandlerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("extension %s: decode response: %w", h.endpoint, err)
+	}
+	if out.Error != "" {
+		return nil, fmt.Errorf("extension %s: %s", h.endpoint, out.Error)
+	}
+	return out.Output, nil
+}
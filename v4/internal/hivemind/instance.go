@@ -0,0 +1,167 @@
+package hivemind
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Alartist40/cynapse/internal/core"
+)
+
+// instanceRow is the raw persisted state of a BeeInstance, used as the unit
+// of optimistic-concurrency read/modify/write in transitionInstance.
+type instanceRow struct {
+	workflowID  string
+	state       core.BeeState
+	context     map[string]interface{}
+	currentNode string
+	revision    int
+}
+
+// createInstanceRow inserts a new Queued instance row at revision 0.
+func (e *Engine) createInstanceRow(instanceID, workflowID string) error {
+	_, err := e.db.Exec(
+		`INSERT INTO instances (instance_id, workflow_id, state, context, current_node, start_time, end_time, logs, revision)
+		 VALUES (?, ?, ?, '{}', '', ?, NULL, '[]', 0)`,
+		instanceID, workflowID, string(core.BeeStateQueued), float64(time.Now().Unix()),
+	)
+	return err
+}
+
+func (e *Engine) readInstanceRow(instanceID string) (instanceRow, error) {
+	var workflowID, state, ctxJSON, currentNode string
+	var revision int
+	err := e.db.QueryRow(
+		`SELECT workflow_id, state, context, current_node, revision FROM instances WHERE instance_id = ?`,
+		instanceID,
+	).Scan(&workflowID, &state, &ctxJSON, &currentNode, &revision)
+	if err != nil {
+		return instanceRow{}, err
+	}
+
+	ctx := make(map[string]interface{})
+	if ctxJSON != "" {
+		if err := json.Unmarshal([]byte(ctxJSON), &ctx); err != nil {
+			return instanceRow{}, fmt.Errorf("decode instance context: %w", err)
+		}
+	}
+
+	return instanceRow{
+		workflowID:  workflowID,
+		state:       core.BeeState(state),
+		context:     ctx,
+		currentNode: currentNode,
+		revision:    revision,
+	}, nil
+}
+
+// instanceWorkflowID returns the workflow ID an instance was created for.
+func (e *Engine) instanceWorkflowID(instanceID string) (string, error) {
+	row, err := e.readInstanceRow(instanceID)
+	if err != nil {
+		return "", err
+	}
+	return row.workflowID, nil
+}
+
+// transitionInstance reads the current row, applies mutate to it, and
+// writes the result back with `UPDATE ... WHERE instance_id=? AND
+// revision=?`. If another writer won the race (zero rows affected), it
+// re-reads the fresh row and retries — mutate decides, based on the
+// observed state, whether the transition is still applicable (returning
+// false, nil to bail out as a no-op rather than an error).
+func (e *Engine) transitionInstance(instanceID string, mutate func(row *instanceRow) (bool, error)) error {
+	for {
+		row, err := e.readInstanceRow(instanceID)
+		if err != nil {
+			return err
+		}
+
+		apply, err := mutate(&row)
+		if err != nil {
+			return err
+		}
+		if !apply {
+			return nil
+		}
+
+		ctxJSON, err := json.Marshal(row.context)
+		if err != nil {
+			return fmt.Errorf("encode instance context: %w", err)
+		}
+
+		var endTime interface{}
+		switch row.state {
+		case core.BeeStateCompleted, core.BeeStateFailed, core.BeeStateCancelled:
+			endTime = float64(time.Now().Unix())
+		}
+
+		res, err := e.db.Exec(
+			`UPDATE instances SET state=?, context=?, current_node=?, end_time=COALESCE(?, end_time), revision=revision+1
+			 WHERE instance_id=? AND revision=?`,
+			string(row.state), string(ctxJSON), row.currentNode, endTime, instanceID, row.revision,
+		)
+		if err != nil {
+			return err
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			return nil
+		}
+		// Lost the race to a concurrent writer (another engine process, or a
+		// Kill racing a node-completion callback) — retry against fresh data.
+	}
+}
+
+// recoverInstances runs once at startup: any instance left Running or
+// Paused by a process that never reached a terminal state is either resumed
+// from its last completed node (Config.ResumeOnStartup) or marked Cancelled.
+func (e *Engine) recoverInstances() {
+	rows, err := e.db.Query(
+		`SELECT instance_id FROM instances WHERE state IN (?, ?)`,
+		string(core.BeeStateRunning), string(core.BeeStatePaused),
+	)
+	if err != nil {
+		log.Printf("[HiveMind] recoverInstances: %v", err)
+		return
+	}
+	var instanceIDs []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err == nil {
+			instanceIDs = append(instanceIDs, id)
+		}
+	}
+	rows.Close()
+
+	for _, instanceID := range instanceIDs {
+		if !e.config.ResumeOnStartup {
+			if err := e.transitionInstance(instanceID, func(row *instanceRow) (bool, error) {
+				row.state = core.BeeStateCancelled
+				return true, nil
+			}); err != nil {
+				log.Printf("[HiveMind] cancel orphaned instance %s: %v", instanceID, err)
+			}
+			continue
+		}
+
+		row, err := e.readInstanceRow(instanceID)
+		if err != nil {
+			log.Printf("[HiveMind] recover %s: %v", instanceID, err)
+			continue
+		}
+		wf, err := e.LoadWorkflow(row.workflowID)
+		if err != nil {
+			log.Printf("[HiveMind] recover %s: load workflow %s: %v", instanceID, row.workflowID, err)
+			continue
+		}
+
+		log.Printf("[HiveMind] resuming instance %s from node %q", instanceID, row.currentNode)
+		go func(instanceID string, wf core.Workflow, initialInputs map[string]interface{}) {
+			if _, err := e.runInstance(context.Background(), instanceID, wf, initialInputs); err != nil {
+				log.Printf("[HiveMind] resumed instance %s ended in error: %v", instanceID, err)
+			}
+		}(instanceID, *wf, row.context)
+	}
+}
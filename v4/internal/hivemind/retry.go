@@ -0,0 +1,153 @@
+package hivemind
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/Alartist40/cynapse/internal/core"
+)
+
+// RetryPolicy controls how a node's handler call is retried on failure.
+// Durations are expressed in seconds to match the repo's JSON/YAML node
+// config convention (see TextChunkerHandler's chunk_size/overlap).
+type RetryPolicy struct {
+	MaxAttempts     int      `json:"max_attempts" yaml:"max_attempts"`
+	InitialBackoff  float64  `json:"initial_backoff" yaml:"initial_backoff"` // seconds
+	MaxBackoff      float64  `json:"max_backoff" yaml:"max_backoff"`         // seconds
+	Multiplier      float64  `json:"multiplier" yaml:"multiplier"`
+	Jitter          float64  `json:"jitter" yaml:"jitter"` // fraction, e.g. 0.2 = +/-20%
+	Retryable       bool     `json:"retryable" yaml:"retryable"`
+	RetryableErrors []string `json:"retryable_errors" yaml:"retryable_errors"`
+	Timeout         float64  `json:"timeout" yaml:"timeout"` // per-attempt, seconds
+}
+
+// DefaultRetryPolicy is used when neither the node nor Config.DefaultRetry
+// specify one: a single attempt with the historical 30s per-attempt timeout.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    1,
+		InitialBackoff: 1,
+		MaxBackoff:     30,
+		Multiplier:     2,
+		Jitter:         0.2,
+		Timeout:        30,
+	}
+}
+
+// retryPolicyForNode resolves the effective policy for node: its own
+// Config["retry"] block overrides fields on top of Config.DefaultRetry,
+// which itself overrides the built-in default.
+func (e *Engine) retryPolicyForNode(node core.Node) RetryPolicy {
+	policy := DefaultRetryPolicy()
+	if e.config.DefaultRetry != nil {
+		policy = *e.config.DefaultRetry
+	}
+
+	raw, ok := node.Config["retry"].(map[string]interface{})
+	if !ok {
+		return policy
+	}
+
+	if v, ok := raw["max_attempts"].(float64); ok {
+		policy.MaxAttempts = int(v)
+	}
+	if v, ok := raw["initial_backoff"].(float64); ok {
+		policy.InitialBackoff = v
+	}
+	if v, ok := raw["max_backoff"].(float64); ok {
+		policy.MaxBackoff = v
+	}
+	if v, ok := raw["multiplier"].(float64); ok {
+		policy.Multiplier = v
+	}
+	if v, ok := raw["jitter"].(float64); ok {
+		policy.Jitter = v
+	}
+	if v, ok := raw["timeout"].(float64); ok {
+		policy.Timeout = v
+	}
+	if v, ok := raw["retryable"].(bool); ok {
+		policy.Retryable = v
+	}
+	if v, ok := raw["retryable_errors"].([]interface{}); ok {
+		policy.RetryableErrors = nil
+		for _, s := range v {
+			if str, ok := s.(string); ok {
+				policy.RetryableErrors = append(policy.RetryableErrors, str)
+			}
+		}
+	}
+
+	return policy
+}
+
+// backoffDelay computes min(initial * multiplier^attempt, max) * (1 +/- jitter)
+// for the given zero-indexed retry attempt.
+func (p RetryPolicy) backoffDelay(attempt int) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = 1
+	}
+	mult := p.Multiplier
+	if mult <= 0 {
+		mult = 2
+	}
+	max := p.MaxBackoff
+	if max <= 0 {
+		max = 30
+	}
+
+	delay := initial * math.Pow(mult, float64(attempt))
+	if delay > max {
+		delay = max
+	}
+
+	if p.Jitter > 0 {
+		spread := delay * p.Jitter
+		delay += (rand.Float64()*2 - 1) * spread
+		if delay < 0 {
+			delay = 0
+		}
+	}
+
+	return time.Duration(delay * float64(time.Second))
+}
+
+// attemptTimeout returns the per-attempt handler timeout, defaulting to 30s.
+func (p RetryPolicy) attemptTimeout() time.Duration {
+	if p.Timeout <= 0 {
+		return 30 * time.Second
+	}
+	return time.Duration(p.Timeout * float64(time.Second))
+}
+
+// shouldRetry reports whether err warrants another attempt under policy,
+// given the attempt number just completed (1-indexed) out of MaxAttempts.
+func (p RetryPolicy) shouldRetry(err error, attemptsSoFar int) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, core.ErrNonRetryable) {
+		return false
+	}
+	maxAttempts := p.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	if attemptsSoFar >= maxAttempts {
+		return false
+	}
+	if p.Retryable {
+		return true
+	}
+	msg := err.Error()
+	for _, substr := range p.RetryableErrors {
+		if strings.Contains(msg, substr) {
+			return true
+		}
+	}
+	return false
+}
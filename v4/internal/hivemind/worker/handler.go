@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+)
+
+// RemoteHandler implements core.NodeHandler by delegating execution to
+// whichever connected agent advertises nodeType, instead of running a
+// handler in-process. Register it the same way as any other handler:
+//
+//	broker := worker.NewBroker(token)
+//	engine.RegisterHandler("meerkat_remote", worker.NewRemoteHandler(broker, "meerkat_remote"))
+type RemoteHandler struct {
+	broker   *Broker
+	nodeType string
+}
+
+// NewRemoteHandler creates a handler that delegates nodeType nodes to
+// agents registered with broker.
+func NewRemoteHandler(broker *Broker, nodeType string) *RemoteHandler {
+	return &RemoteHandler{broker: broker, nodeType: nodeType}
+}
+
+// Execute implements core.NodeHandler.
+func (h *RemoteHandler) Execute(ctx context.Context, inputs map[string]interface{}, config map[string]interface{}) (map[string]interface{}, error) {
+	task := Task{
+		ID:     newID(),
+		Type:   h.nodeType,
+		Inputs: inputs,
+		Config: config,
+	}
+
+	res, err := h.broker.Submit(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("worker: %w", err)
+	}
+	if res.Error != "" {
+		return nil, fmt.Errorf("worker: agent reported: %s", res.Error)
+	}
+	return res.Output, nil
+}
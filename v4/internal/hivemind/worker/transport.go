@@ -0,0 +1,109 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// pollWait bounds each long-poll round trip; agents reconnect immediately
+// on a timeout, so this just keeps idle connections from hanging forever.
+const pollWait = 25 * time.Second
+
+type registerRequest struct {
+	Token     string   `json:"token"`
+	Platform  string   `json:"platform"`
+	NodeTypes []string `json:"node_types"`
+	MaxProcs  int      `json:"max_procs"`
+}
+
+type registerResponse struct {
+	AgentID string `json:"agent_id"`
+}
+
+type pollRequest struct {
+	AgentID   string   `json:"agent_id"`
+	NodeTypes []string `json:"node_types"`
+}
+
+type pollResponse struct {
+	Task *Task `json:"task,omitempty"`
+}
+
+type heartbeatRequest struct {
+	AgentID string `json:"agent_id"`
+}
+
+// Handler returns an http.Handler exposing the broker's agent-facing
+// endpoints: POST /register, POST /poll, POST /heartbeat, POST /result.
+// Mount it under a path prefix on the engine's admin server.
+func (b *Broker) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/register", b.handleRegister)
+	mux.HandleFunc("/poll", b.handlePoll)
+	mux.HandleFunc("/heartbeat", b.handleHeartbeat)
+	mux.HandleFunc("/result", b.handleResult)
+	return mux
+}
+
+func (b *Broker) handleRegister(w http.ResponseWriter, r *http.Request) {
+	var req registerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	id, err := b.Register(req.Token, req.Platform, req.NodeTypes, req.MaxProcs)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	json.NewEncoder(w).Encode(registerResponse{AgentID: id})
+}
+
+func (b *Broker) handlePoll(w http.ResponseWriter, r *http.Request) {
+	var req pollRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+
+	task, ok := b.Poll(req.AgentID, req.NodeTypes, pollWait)
+	if !ok {
+		json.NewEncoder(w).Encode(pollResponse{})
+		return
+	}
+	json.NewEncoder(w).Encode(pollResponse{Task: &task})
+}
+
+func (b *Broker) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	var req heartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := b.Heartbeat(req.AgentID); err != nil {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (b *Broker) handleResult(w http.ResponseWriter, r *http.Request) {
+	var res Result
+	if err := json.NewDecoder(r.Body).Decode(&res); err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if err := b.PostResult(res); err != nil {
+		status := http.StatusNotFound
+		if errors.Is(err, ErrAgentMismatch) {
+			status = http.StatusForbidden
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
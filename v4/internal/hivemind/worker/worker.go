@@ -0,0 +1,266 @@
+// Package worker implements the remote worker protocol: node execution
+// delegated to detached Go agents (e.g. a scanner box in a DMZ, or a
+// container running alongside a target) instead of the local engine
+// process. Agents connect outbound and long-poll over plain HTTP — the
+// repo has no websocket/gRPC dependency vendored, and polling fits the
+// "poll-based CI runner" model this is patterned on.
+package worker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrAgentMismatch is returned by PostResult when the posting agent isn't
+// the one the broker actually dispatched the task to.
+var ErrAgentMismatch = errors.New("worker: result posted by agent other than the one dispatched")
+
+// Task is a unit of node work delegated to a remote agent.
+type Task struct {
+	ID     string                 `json:"id"`
+	Type   string                 `json:"type"` // matches a core.Node.Type the agent advertised
+	Inputs map[string]interface{} `json:"inputs"`
+	Config map[string]interface{} `json:"config"`
+}
+
+// Result is an agent's response to a delegated Task. AgentID must match the
+// agent the broker actually dispatched TaskID to — see PostResult — so a
+// result can't be forged by anyone who merely knows or guesses a TaskID.
+type Result struct {
+	TaskID  string                 `json:"task_id"`
+	AgentID string                 `json:"agent_id"`
+	Output  map[string]interface{} `json:"output,omitempty"`
+	Error   string                 `json:"error,omitempty"`
+}
+
+// AgentInfo describes a connected remote agent.
+type AgentInfo struct {
+	ID            string
+	Platform      string
+	NodeTypes     []string
+	MaxProcs      int
+	InFlight      int
+	LastHeartbeat time.Time
+}
+
+// inFlightTask tracks a dispatched task awaiting its result, so it can be
+// requeued if the owning agent goes silent.
+type inFlightTask struct {
+	task    Task
+	agentID string
+}
+
+// Broker is the engine-side half of the remote worker protocol: it queues
+// tasks per node type, hands them to polling agents (respecting each
+// agent's max_procs), and requeues in-flight work if an agent's heartbeat
+// goes stale.
+type Broker struct {
+	mu               sync.Mutex
+	cond             *sync.Cond
+	tokens           map[string]bool
+	agents           map[string]*AgentInfo
+	queues           map[string][]Task
+	inFlight         map[string]*inFlightTask
+	waiters          map[string]chan Result
+	heartbeatTimeout time.Duration
+}
+
+// NewBroker creates a Broker accepting agents that present one of tokens.
+func NewBroker(tokens ...string) *Broker {
+	allowed := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		allowed[t] = true
+	}
+	b := &Broker{
+		tokens:           allowed,
+		agents:           make(map[string]*AgentInfo),
+		queues:           make(map[string][]Task),
+		inFlight:         make(map[string]*inFlightTask),
+		waiters:          make(map[string]chan Result),
+		heartbeatTimeout: 90 * time.Second,
+	}
+	b.cond = sync.NewCond(&b.mu)
+	go b.reaper()
+	return b
+}
+
+// Register authenticates an agent and enrolls it in the broker, returning
+// its assigned ID.
+func (b *Broker) Register(token, platform string, nodeTypes []string, maxProcs int) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.tokens) > 0 && !b.tokens[token] {
+		return "", fmt.Errorf("worker: invalid agent token")
+	}
+	if maxProcs <= 0 {
+		maxProcs = 1
+	}
+
+	id := newID()
+	b.agents[id] = &AgentInfo{
+		ID:            id,
+		Platform:      platform,
+		NodeTypes:     nodeTypes,
+		MaxProcs:      maxProcs,
+		LastHeartbeat: time.Now(),
+	}
+	return id, nil
+}
+
+// Heartbeat records that agentID is still alive.
+func (b *Broker) Heartbeat(agentID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	agent, ok := b.agents[agentID]
+	if !ok {
+		return fmt.Errorf("worker: unknown agent %s", agentID)
+	}
+	agent.LastHeartbeat = time.Now()
+	return nil
+}
+
+// Submit enqueues a task for the given nodeType and blocks until a result
+// arrives, ctx is cancelled, or the workflow's node timeout elapses.
+func (b *Broker) Submit(ctx context.Context, task Task) (Result, error) {
+	ch := make(chan Result, 1)
+
+	b.mu.Lock()
+	b.waiters[task.ID] = ch
+	b.queues[task.Type] = append(b.queues[task.Type], task)
+	b.mu.Unlock()
+	b.cond.Broadcast()
+
+	select {
+	case res := <-ch:
+		return res, nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		delete(b.waiters, task.ID)
+		delete(b.inFlight, task.ID)
+		b.mu.Unlock()
+		return Result{}, ctx.Err()
+	}
+}
+
+// Poll blocks (up to wait) for a task matching one of nodeTypes that
+// agentID has a free slot to run, then marks it in-flight against agentID.
+func (b *Broker) Poll(agentID string, nodeTypes []string, wait time.Duration) (Task, bool) {
+	deadline := time.Now().Add(wait)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for {
+		if task, ok := b.takeLocked(agentID, nodeTypes); ok {
+			return task, true
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return Task{}, false
+		}
+
+		timer := time.AfterFunc(remaining, b.cond.Broadcast)
+		b.cond.Wait()
+		timer.Stop()
+
+		if time.Now().After(deadline) {
+			if task, ok := b.takeLocked(agentID, nodeTypes); ok {
+				return task, true
+			}
+			return Task{}, false
+		}
+	}
+}
+
+// takeLocked pops the first queued task matching nodeTypes for agentID,
+// provided the agent is registered and under its max_procs limit. Caller
+// must hold b.mu.
+func (b *Broker) takeLocked(agentID string, nodeTypes []string) (Task, bool) {
+	agent, ok := b.agents[agentID]
+	if !ok || agent.InFlight >= agent.MaxProcs {
+		return Task{}, false
+	}
+
+	for _, nt := range nodeTypes {
+		q := b.queues[nt]
+		if len(q) == 0 {
+			continue
+		}
+		task := q[0]
+		b.queues[nt] = q[1:]
+		b.inFlight[task.ID] = &inFlightTask{task: task, agentID: agentID}
+		agent.InFlight++
+		return task, true
+	}
+	return Task{}, false
+}
+
+// PostResult delivers an agent's result for a previously dispatched task.
+// res.AgentID must match the agent takeLocked actually handed TaskID to —
+// otherwise any caller who reaches this endpoint and knows (or guesses) a
+// TaskID could inject a fabricated result into a running workflow.
+func (b *Broker) PostResult(res Result) error {
+	b.mu.Lock()
+	in, ok := b.inFlight[res.TaskID]
+	if ok && in.agentID != res.AgentID {
+		b.mu.Unlock()
+		return ErrAgentMismatch
+	}
+	if ok {
+		delete(b.inFlight, res.TaskID)
+		if agent, ok := b.agents[in.agentID]; ok && agent.InFlight > 0 {
+			agent.InFlight--
+		}
+	}
+	waiter, hasWaiter := b.waiters[res.TaskID]
+	delete(b.waiters, res.TaskID)
+	b.mu.Unlock()
+	b.cond.Broadcast()
+
+	if !ok {
+		return fmt.Errorf("worker: no in-flight task %s", res.TaskID)
+	}
+	if hasWaiter {
+		waiter <- res
+	}
+	return nil
+}
+
+// reaper requeues tasks whose owning agent has gone silent past
+// heartbeatTimeout, and drops the stale agent registration.
+func (b *Broker) reaper() {
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+	for range ticker.C {
+		b.mu.Lock()
+		now := time.Now()
+		for id, agent := range b.agents {
+			if now.Sub(agent.LastHeartbeat) <= b.heartbeatTimeout {
+				continue
+			}
+			for taskID, in := range b.inFlight {
+				if in.agentID != id {
+					continue
+				}
+				b.queues[in.task.Type] = append(b.queues[in.task.Type], in.task)
+				delete(b.inFlight, taskID)
+			}
+			delete(b.agents, id)
+		}
+		b.mu.Unlock()
+		b.cond.Broadcast()
+	}
+}
+
+func newID() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
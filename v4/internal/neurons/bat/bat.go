@@ -4,10 +4,12 @@ package bat
 import (
 	"context"
 	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"github.com/Alartist40/cynapse/internal/core"
@@ -27,9 +29,11 @@ func New(shardsDir string, threshold int) *Neuron {
 	return &Neuron{shardsDir: shardsDir, threshold: threshold}
 }
 
-func (n *Neuron) ID() string           { return "bat" }
-func (n *Neuron) Name() string         { return "Bat — USB Hardware Auth" }
-func (n *Neuron) Capabilities() []string { return []string{"usb_auth", "shard_verify", "list_devices"} }
+func (n *Neuron) ID() string   { return "bat" }
+func (n *Neuron) Name() string { return "Bat — USB Hardware Auth" }
+func (n *Neuron) Capabilities() []string {
+	return []string{"usb_auth", "shard_verify", "list_devices", "split", "rotate"}
+}
 
 func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, error) {
 	switch task.Operation {
@@ -54,43 +58,140 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 		}
 		return core.Result{Success: true, Output: fmt.Sprintf("Devices: %s", strings.Join(devices, ", "))}, nil
 
+	case "split":
+		devices := strings.Split(task.Params["devices"], ",")
+		if err := n.split(devices, task.Params["threshold"], task.Payload); err != nil {
+			return core.Result{Success: false, Output: err.Error()}, nil
+		}
+		return core.Result{Success: true, Output: fmt.Sprintf("Split secret into %d shards", len(devices))}, nil
+
+	case "rotate":
+		devices := strings.Split(task.Params["devices"], ",")
+		if err := n.rotate(devices); err != nil {
+			return core.Result{Success: false, Output: err.Error()}, nil
+		}
+		return core.Result{Success: true, Output: fmt.Sprintf("Rotated secret across %d new shards", len(devices))}, nil
+
 	default:
 		return core.Result{}, fmt.Errorf("bat: unknown operation %s", task.Operation)
 	}
 }
 
 func (n *Neuron) authenticate(devices []string) (bool, error) {
-	var shards [][]byte
+	shares, err := n.loadShares(devices)
+	if err != nil {
+		return false, err
+	}
+
+	secret, err := combineShares(shares)
+	if err != nil {
+		return false, fmt.Errorf("bat: reconstruct secret: %w", err)
+	}
+
+	return n.verifySecret(secret)
+}
 
+// loadShares reads the named devices' .shard files (each laid out as
+// x || y_bytes by splitSecret), skipping any device whose USB stick isn't
+// actually present, and requires at least the configured threshold.
+func (n *Neuron) loadShares(devices []string) ([][]byte, error) {
+	var shares [][]byte
 	for _, dev := range devices {
 		path := filepath.Join(n.shardsDir, dev+".shard")
 		data, err := os.ReadFile(path)
 		if err != nil {
-			continue // Device not present
+			continue // device not present
 		}
-		shards = append(shards, data)
+		shares = append(shares, data)
+	}
+	if len(shares) < n.threshold {
+		return nil, fmt.Errorf("only %d of %d shards present", len(shares), n.threshold)
 	}
+	return shares, nil
+}
 
-	if len(shards) < n.threshold {
-		return false, fmt.Errorf("only %d of %d shards present", len(shards), n.threshold)
+// verifySecret hashes secret and compares it against master.hash in
+// constant time, so a mismatched guess can't be distinguished by timing.
+func (n *Neuron) verifySecret(secret []byte) (bool, error) {
+	hash := sha256.Sum256(secret)
+	expected, err := os.ReadFile(filepath.Join(n.shardsDir, "master.hash"))
+	if err != nil {
+		return false, fmt.Errorf("cannot read master hash: %w", err)
 	}
+	want, err := hex.DecodeString(strings.TrimSpace(string(expected)))
+	if err != nil {
+		return false, fmt.Errorf("bat: malformed master.hash: %w", err)
+	}
+	return subtle.ConstantTimeCompare(hash[:], want) == 1, nil
+}
 
-	// Simplified: XOR-combine shards (production would use Shamir SSS)
-	combined := make([]byte, len(shards[0]))
-	copy(combined, shards[0])
-	for _, s := range shards[1:] {
-		for i := range combined {
-			if i < len(s) {
-				combined[i] ^= s[i]
-			}
+// split generates a fresh (threshold, len(devices)) Shamir split of secret
+// and writes one shard file per device plus master.hash for verification.
+func (n *Neuron) split(devices []string, thresholdOverride string, secret []byte) error {
+	if len(secret) == 0 {
+		return fmt.Errorf("bat: split requires a non-empty secret payload")
+	}
+	k := n.threshold
+	if thresholdOverride != "" {
+		v, err := strconv.Atoi(thresholdOverride)
+		if err != nil {
+			return fmt.Errorf("bat: invalid threshold %q: %w", thresholdOverride, err)
 		}
+		k = v
 	}
 
-	hash := sha256.Sum256(combined)
-	expected, err := os.ReadFile(filepath.Join(n.shardsDir, "master.hash"))
+	shares, err := splitSecret(secret, len(devices), k)
 	if err != nil {
-		return false, fmt.Errorf("cannot read master hash: %w", err)
+		return err
+	}
+	if err := os.MkdirAll(n.shardsDir, 0700); err != nil {
+		return fmt.Errorf("bat: create shards dir: %w", err)
+	}
+	for i, dev := range devices {
+		path := filepath.Join(n.shardsDir, dev+".shard")
+		if err := os.WriteFile(path, shares[i], 0600); err != nil {
+			return fmt.Errorf("bat: write shard %s: %w", dev, err)
+		}
+	}
+
+	hash := sha256.Sum256(secret)
+	hashPath := filepath.Join(n.shardsDir, "master.hash")
+	if err := os.WriteFile(hashPath, []byte(hex.EncodeToString(hash[:])), 0600); err != nil {
+		return fmt.Errorf("bat: write master hash: %w", err)
+	}
+	return nil
+}
+
+// rotate reconstructs the current secret from whichever existing shards are
+// present, then re-splits it with a fresh random polynomial onto devices.
+// Any shard not rewritten here (e.g. a USB stick left unplugged, or one
+// from before a device list change) no longer combines with the new
+// shares, invalidating it without needing to track it down and destroy it.
+func (n *Neuron) rotate(devices []string) error {
+	entries, err := os.ReadDir(n.shardsDir)
+	if err != nil {
+		return fmt.Errorf("bat: read shards dir: %w", err)
+	}
+	var existing []string
+	for _, e := range entries {
+		if strings.HasSuffix(e.Name(), ".shard") {
+			existing = append(existing, strings.TrimSuffix(e.Name(), ".shard"))
+		}
+	}
+
+	shares, err := n.loadShares(existing)
+	if err != nil {
+		return err
+	}
+	secret, err := combineShares(shares)
+	if err != nil {
+		return fmt.Errorf("bat: reconstruct secret: %w", err)
+	}
+	if ok, err := n.verifySecret(secret); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("bat: reconstructed secret failed master hash check, refusing to rotate")
 	}
 
-	return hex.EncodeToString(hash[:]) == strings.TrimSpace(string(expected)), nil
+	return n.split(devices, "", secret)
 }
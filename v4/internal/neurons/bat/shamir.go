@@ -0,0 +1,141 @@
+package bat
+
+import (
+	"crypto/rand"
+	"fmt"
+)
+
+// gfExp and gfLog are precomputed log/antilog tables over GF(2^8), reduced
+// modulo the AES standard polynomial x^8+x^4+x^3+x+1 (0x11B), generated
+// from the primitive element 3. gfExp is doubled in length so gfMul can
+// index it without wrapping the exponent sum.
+var gfExp [510]byte
+var gfLog [256]byte
+
+func init() {
+	x := byte(1)
+	for i := 0; i < 255; i++ {
+		gfExp[i] = x
+		gfLog[x] = byte(i)
+		x = gfMulSlow(x, 0x03)
+	}
+	for i := 255; i < 510; i++ {
+		gfExp[i] = gfExp[i-255]
+	}
+}
+
+// gfMulSlow multiplies two GF(2^8) elements by hand (carry-less multiply
+// followed by reduction by 0x11B); used only to build the log/antilog
+// tables above, since every other multiply goes through gfMul.
+func gfMulSlow(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		if b&1 != 0 {
+			p ^= a
+		}
+		carry := a & 0x80
+		a <<= 1
+		if carry != 0 {
+			a ^= 0x1B
+		}
+		b >>= 1
+	}
+	return p
+}
+
+func gfAdd(a, b byte) byte { return a ^ b } // addition and subtraction coincide in GF(2^n)
+
+func gfMul(a, b byte) byte {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return gfExp[int(gfLog[a])+int(gfLog[b])]
+}
+
+func gfDiv(a, b byte) byte {
+	if a == 0 {
+		return 0
+	}
+	if b == 0 {
+		panic("bat: division by zero in GF(256)")
+	}
+	return gfExp[int(gfLog[a])+255-int(gfLog[b])]
+}
+
+// evalPoly evaluates the polynomial with coeffs[0] as the constant term at
+// x, via Horner's method, entirely in GF(2^8).
+func evalPoly(coeffs []byte, x byte) byte {
+	var result byte
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		result = gfAdd(gfMul(result, x), coeffs[i])
+	}
+	return result
+}
+
+// splitSecret runs (k, n) Shamir Secret Sharing on secret: for every byte,
+// a fresh random degree-(k-1) polynomial with that byte as its constant
+// term is evaluated at n distinct nonzero x-coordinates. Each returned
+// share is laid out as x || y_bytes, one y byte per byte of secret.
+func splitSecret(secret []byte, n, k int) ([][]byte, error) {
+	if k < 1 || n < k || n > 255 {
+		return nil, fmt.Errorf("bat: invalid shamir params n=%d k=%d", n, k)
+	}
+
+	shares := make([][]byte, n)
+	for i := range shares {
+		shares[i] = make([]byte, 1+len(secret))
+		shares[i][0] = byte(i + 1) // x=0 is reserved for the secret itself
+	}
+
+	coeffs := make([]byte, k)
+	rnd := make([]byte, k-1)
+	for byteIdx, secretByte := range secret {
+		if _, err := rand.Read(rnd); err != nil {
+			return nil, fmt.Errorf("bat: rand: %w", err)
+		}
+		coeffs[0] = secretByte
+		copy(coeffs[1:], rnd)
+		for _, share := range shares {
+			share[1+byteIdx] = evalPoly(coeffs, share[0])
+		}
+	}
+	return shares, nil
+}
+
+// combineShares reconstructs the secret from k or more shares via Lagrange
+// interpolation at x=0, byte by byte: secret_byte = Σ y_i · Π (x_j / (x_j - x_i))
+// for j != i, all arithmetic in GF(2^8).
+func combineShares(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, fmt.Errorf("bat: no shares to combine")
+	}
+	secretLen := len(shares[0]) - 1
+	seenX := make(map[byte]bool, len(shares))
+	for _, s := range shares {
+		if len(s) != secretLen+1 {
+			return nil, fmt.Errorf("bat: mismatched shard length")
+		}
+		if seenX[s[0]] {
+			return nil, fmt.Errorf("bat: duplicate share x-coordinate %d", s[0])
+		}
+		seenX[s[0]] = true
+	}
+
+	secret := make([]byte, secretLen)
+	for byteIdx := 0; byteIdx < secretLen; byteIdx++ {
+		var acc byte
+		for i, si := range shares {
+			num, den := byte(1), byte(1)
+			for j, sj := range shares {
+				if i == j {
+					continue
+				}
+				num = gfMul(num, sj[0])
+				den = gfMul(den, gfAdd(sj[0], si[0]))
+			}
+			acc = gfAdd(acc, gfMul(si[1+byteIdx], gfDiv(num, den)))
+		}
+		secret[byteIdx] = acc
+	}
+	return secret, nil
+}
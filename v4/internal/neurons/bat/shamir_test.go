@@ -0,0 +1,61 @@
+package bat
+
+import "testing"
+
+func TestSplitCombineRoundTrip(t *testing.T) {
+	secret := []byte("correct horse battery staple")
+	shares, err := splitSecret(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("splitSecret: %v", err)
+	}
+
+	got, err := combineShares(shares[:3])
+	if err != nil {
+		t.Fatalf("combineShares: %v", err)
+	}
+	if string(got) != string(secret) {
+		t.Fatalf("combineShares with threshold shares = %q, want %q", got, secret)
+	}
+
+	got, err = combineShares(shares)
+	if err != nil {
+		t.Fatalf("combineShares with all shares: %v", err)
+	}
+	if string(got) != string(secret) {
+		t.Fatalf("combineShares with all shares = %q, want %q", got, secret)
+	}
+}
+
+func TestCombineSharesDuplicateXCoordinate(t *testing.T) {
+	secret := []byte("topsecret")
+	shares, err := splitSecret(secret, 5, 3)
+	if err != nil {
+		t.Fatalf("splitSecret: %v", err)
+	}
+
+	// Simulate a caller-controlled duplicate device name (e.g. "devices":
+	// "usb1,usb1") collapsing two distinct shares to the same x-coordinate.
+	dup := make([][]byte, 3)
+	copy(dup, shares[:3])
+	dup[1] = shares[0]
+
+	if _, err := combineShares(dup); err == nil {
+		t.Fatal("expected error for duplicate share x-coordinates, got nil")
+	}
+}
+
+func TestCombineSharesMismatchedLength(t *testing.T) {
+	shares := [][]byte{
+		{1, 0xAA, 0xBB},
+		{2, 0xCC},
+	}
+	if _, err := combineShares(shares); err == nil {
+		t.Fatal("expected error for mismatched shard length, got nil")
+	}
+}
+
+func TestCombineSharesEmpty(t *testing.T) {
+	if _, err := combineShares(nil); err == nil {
+		t.Fatal("expected error for no shares, got nil")
+	}
+}
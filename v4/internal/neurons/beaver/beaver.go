@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Alartist40/cynapse/internal/alerts"
 	"github.com/Alartist40/cynapse/internal/core"
 )
 
@@ -24,7 +25,8 @@ type Rule struct {
 
 // Neuron implements the Beaver firewall generator neuron.
 type Neuron struct {
-	backend string // "iptables" or "nftables"
+	backend   string // "iptables" or "nftables"
+	alertSink alerts.Sink
 }
 
 func New(backend string) *Neuron {
@@ -34,6 +36,37 @@ func New(backend string) *Neuron {
 	return &Neuron{backend: backend}
 }
 
+// SetAlertSink wires an alerts.Sink in, satisfying alerts.Emitter so
+// hivemind.Engine.RegisterNeuron can hook this up automatically.
+func (n *Neuron) SetAlertSink(sink alerts.Sink) {
+	n.alertSink = sink
+}
+
+// sensitivePorts are ports a rule that ACCEPTs from 0.0.0.0/0 is flagged
+// for, on the theory that generating such a rule is itself worth a human
+// glancing at before it's actually applied.
+var sensitivePorts = map[int]bool{
+	22: true, 23: true, 3389: true, 3306: true, 5432: true, 6379: true, 9200: true, 27017: true,
+}
+
+// flagOverlyPermissive pages a human when fromNaturalLanguage produced a
+// rule that opens a sensitive port to the entire internet — the generator
+// doing exactly what it was asked doesn't mean the ask was a good idea.
+func (n *Neuron) flagOverlyPermissive(ctx context.Context, query string, rule Rule) {
+	if n.alertSink == nil {
+		return
+	}
+	if rule.Action != "ACCEPT" || rule.Source != "0.0.0.0/0" || !sensitivePorts[rule.Port] {
+		return
+	}
+	n.alertSink.Emit(ctx, alerts.Alert{
+		Severity: alerts.SeverityHigh,
+		Source:   n.ID(),
+		Message:  fmt.Sprintf("beaver generated an ACCEPT rule for port %d from 0.0.0.0/0", rule.Port),
+		Details:  map[string]string{"query": query, "iptables": rule.IPTables},
+	})
+}
+
 func (n *Neuron) ID() string             { return "beaver" }
 func (n *Neuron) Name() string           { return "Beaver — Firewall Generator" }
 func (n *Neuron) Capabilities() []string { return []string{"generate_rules", "parse_query", "list_rules"} }
@@ -48,6 +81,7 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 		}
 		var lines []string
 		for _, r := range rules {
+			n.flagOverlyPermissive(ctx, query, r)
 			if n.backend == "nftables" {
 				lines = append(lines, r.NFT)
 			} else {
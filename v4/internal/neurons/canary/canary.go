@@ -2,31 +2,87 @@
 package canary
 
 import (
+	"bufio"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
 	"fmt"
-	"math/rand"
+	"math/big"
+	mathrand "math/rand"
 	"net"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/Alartist40/cynapse/internal/alerts"
 	"github.com/Alartist40/cynapse/internal/core"
 )
 
+// hitLogSize bounds how many recent connections each trap remembers.
+const hitLogSize = 50
+
+// hitPayloadCap is the most bytes of a probe's payload a hit record keeps.
+const hitPayloadCap = 256
+
+// Hit is one recorded interaction with a trap: who connected, what TLS SNI
+// (if any) they presented, and the first bytes of whatever they sent.
+type Hit struct {
+	RemoteAddr string    `json:"remote_addr"`
+	SNI        string    `json:"sni,omitempty"`
+	Payload    string    `json:"payload,omitempty"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// hitRing is a fixed-size ring buffer of the most recent Hits for a trap.
+type hitRing struct {
+	entries [hitLogSize]Hit
+	next    int
+	count   int
+}
+
+func (r *hitRing) add(h Hit) {
+	r.entries[r.next] = h
+	r.next = (r.next + 1) % hitLogSize
+	if r.count < hitLogSize {
+		r.count++
+	}
+}
+
+func (r *hitRing) list() []Hit {
+	out := make([]Hit, r.count)
+	start := (r.next - r.count + hitLogSize) % hitLogSize
+	for i := 0; i < r.count; i++ {
+		out[i] = r.entries[(start+i)%hitLogSize]
+	}
+	return out
+}
+
 // Trap represents a deception endpoint.
 type Trap struct {
 	Port     int       `json:"port"`
-	Protocol string    `json:"protocol"`
+	Family   string    `json:"family"`   // "ipv4", "ipv6", or "dual"
+	Protocol string    `json:"protocol"` // "tcp", "udp", "tls", or "http"
+	Persona  string    `json:"persona,omitempty"`
+	SNI      string    `json:"sni,omitempty"`
 	Active   bool      `json:"active"`
 	Hits     int       `json:"hits"`
 	LastHit  time.Time `json:"last_hit,omitempty"`
+
+	hits hitRing
 }
 
 // Neuron implements the Canary deception neuron.
 type Neuron struct {
-	traps    map[int]*Trap
-	mu       sync.RWMutex
-	stopChan chan struct{}
+	traps     map[int]*Trap
+	mu        sync.RWMutex
+	stopChan  chan struct{}
+	alertSink alerts.Sink
 }
 
 func New() *Neuron {
@@ -36,9 +92,17 @@ func New() *Neuron {
 	}
 }
 
-func (n *Neuron) ID() string             { return "canary" }
-func (n *Neuron) Name() string           { return "Canary — Network Deception" }
-func (n *Neuron) Capabilities() []string { return []string{"deploy_trap", "list_traps", "generate_decoy", "status"} }
+// SetAlertSink wires an alerts.Sink in, satisfying alerts.Emitter so
+// hivemind.Engine.RegisterNeuron can hook this up automatically.
+func (n *Neuron) SetAlertSink(sink alerts.Sink) {
+	n.alertSink = sink
+}
+
+func (n *Neuron) ID() string   { return "canary" }
+func (n *Neuron) Name() string { return "Canary — Network Deception" }
+func (n *Neuron) Capabilities() []string {
+	return []string{"deploy_trap", "list_traps", "generate_decoy", "trap_hits", "status"}
+}
 
 func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, error) {
 	switch task.Operation {
@@ -46,9 +110,16 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 		port := 0
 		fmt.Sscanf(task.Params["port"], "%d", &port)
 		if port == 0 {
-			port = 4444 + rand.Intn(1000)
+			port = 4444 + mathrand.Intn(1000)
+		}
+		trap := &Trap{
+			Port:     port,
+			Family:   normalizeFamily(task.Params["family"]),
+			Protocol: normalizeProtocol(task.Params["protocol"]),
+			Persona:  task.Params["persona"],
+			SNI:      task.Params["sni"],
+			Active:   true,
 		}
-		trap := &Trap{Port: port, Protocol: "tcp", Active: true}
 		n.mu.Lock()
 		n.traps[port] = trap
 		n.mu.Unlock()
@@ -58,7 +129,7 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 
 		return core.Result{
 			Success: true,
-			Output:  fmt.Sprintf("🐦 Canary trap deployed on port %d", port),
+			Output:  fmt.Sprintf("🐦 Canary trap deployed on port %d (%s/%s)", port, trap.Family, trap.Protocol),
 			Details: map[string]string{"port": fmt.Sprintf("%d", port)},
 		}, nil
 
@@ -71,15 +142,34 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 			if !t.Active {
 				status = "INACTIVE"
 			}
-			lines = append(lines, fmt.Sprintf("  Port %d [%s] — %d hits", t.Port, status, t.Hits))
+			lines = append(lines, fmt.Sprintf("  Port %d [%s/%s %s] — %d hits", t.Port, t.Family, t.Protocol, status, t.Hits))
 		}
 		if len(lines) == 0 {
 			return core.Result{Success: true, Output: "No active traps."}, nil
 		}
 		return core.Result{Success: true, Output: "🐦 Active Traps:\n" + strings.Join(lines, "\n")}, nil
 
+	case "trap_hits":
+		port := 0
+		fmt.Sscanf(task.Params["port"], "%d", &port)
+		n.mu.RLock()
+		trap, ok := n.traps[port]
+		var hits []Hit
+		if ok {
+			hits = trap.hits.list()
+		}
+		n.mu.RUnlock()
+		if !ok {
+			return core.Result{Success: false, Output: fmt.Sprintf("no trap on port %d", port)}, nil
+		}
+		body, err := json.Marshal(hits)
+		if err != nil {
+			return core.Result{Success: false, Output: err.Error()}, nil
+		}
+		return core.Result{Success: true, Output: string(body)}, nil
+
 	case "generate_decoy":
-		decoy := n.generateDecoyResponse()
+		decoy := generateDecoyResponse(task.Params["persona"])
 		return core.Result{Success: true, Output: decoy}, nil
 
 	default:
@@ -87,10 +177,103 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 	}
 }
 
+func normalizeFamily(family string) string {
+	switch family {
+	case "ipv4", "ipv6":
+		return family
+	default:
+		return "dual"
+	}
+}
+
+func normalizeProtocol(protocol string) string {
+	switch protocol {
+	case "udp", "tls", "http":
+		return protocol
+	default:
+		return "tcp"
+	}
+}
+
+// networkFor maps a trap's family onto the Go network name for the given
+// base protocol ("tcp" or "udp") — "dual" leaves the family suffix off so
+// the OS binds both address families where it supports it.
+func networkFor(base, family string) string {
+	switch family {
+	case "ipv4":
+		return base + "4"
+	case "ipv6":
+		return base + "6"
+	default:
+		return base
+	}
+}
+
+// addrFor returns the listen address for a trap's family; IPv6-only binds
+// must use "[::]" rather than the bare ":port" net.Listen defaults to.
+func addrFor(family string, port int) string {
+	if family == "ipv6" {
+		return fmt.Sprintf("[::]:%d", port)
+	}
+	return fmt.Sprintf(":%d", port)
+}
+
 func (n *Neuron) listenTrap(ctx context.Context, trap *Trap) {
-	ln, err := net.Listen("tcp", fmt.Sprintf(":%d", trap.Port))
+	switch trap.Protocol {
+	case "udp":
+		n.listenUDP(ctx, trap)
+	case "tls":
+		n.listenTLS(ctx, trap)
+	case "http":
+		n.listenHTTP(ctx, trap)
+	default:
+		n.listenTCP(ctx, trap)
+	}
+}
+
+// recordHit appends a Hit to trap's ring buffer and bumps its counters,
+// under the same lock deploy_trap/list_traps use to read Trap fields, then
+// pages a human via the alert sink (if one's registered) since a trap
+// firing is exactly the kind of thing a counter nobody's watching misses.
+func (n *Neuron) recordHit(ctx context.Context, trap *Trap, remoteAddr, sni string, payload []byte) {
+	if len(payload) > hitPayloadCap {
+		payload = payload[:hitPayloadCap]
+	}
+	n.mu.Lock()
+	trap.Hits++
+	trap.LastHit = time.Now()
+	trap.hits.add(Hit{RemoteAddr: remoteAddr, SNI: sni, Payload: string(payload), Timestamp: trap.LastHit})
+	n.mu.Unlock()
+
+	if n.alertSink == nil {
+		return
+	}
+	details := map[string]string{
+		"port":        fmt.Sprintf("%d", trap.Port),
+		"protocol":    trap.Protocol,
+		"remote_addr": remoteAddr,
+	}
+	if sni != "" {
+		details["sni"] = sni
+	}
+	n.alertSink.Emit(ctx, alerts.Alert{
+		Severity: alerts.SeverityMedium,
+		Source:   n.ID(),
+		Message:  fmt.Sprintf("canary trap on port %d (%s) hit by %s", trap.Port, trap.Protocol, remoteAddr),
+		Details:  details,
+	})
+}
+
+func (n *Neuron) deactivate(trap *Trap) {
+	n.mu.Lock()
+	trap.Active = false
+	n.mu.Unlock()
+}
+
+func (n *Neuron) listenTCP(ctx context.Context, trap *Trap) {
+	ln, err := net.Listen(networkFor("tcp", trap.Family), addrFor(trap.Family, trap.Port))
 	if err != nil {
-		trap.Active = false
+		n.deactivate(trap)
 		return
 	}
 	defer ln.Close()
@@ -105,24 +288,189 @@ func (n *Neuron) listenTrap(ctx context.Context, trap *Trap) {
 		if err != nil {
 			break
 		}
-		n.mu.Lock()
-		trap.Hits++
-		trap.LastHit = time.Now()
-		n.mu.Unlock()
+		n.recordHit(ctx, trap, conn.RemoteAddr().String(), "", readProbe(conn))
+		conn.Write([]byte(generateDecoyResponse(trap.Persona)))
+		conn.Close()
+	}
+	n.deactivate(trap)
+}
 
-		// Send fake response and close
-		conn.Write([]byte(n.generateDecoyResponse()))
+func (n *Neuron) listenUDP(ctx context.Context, trap *Trap) {
+	pc, err := net.ListenPacket(networkFor("udp", trap.Family), addrFor(trap.Family, trap.Port))
+	if err != nil {
+		n.deactivate(trap)
+		return
+	}
+	defer pc.Close()
+
+	go func() {
+		<-ctx.Done()
+		pc.Close()
+	}()
+
+	buf := make([]byte, hitPayloadCap)
+	for {
+		read, addr, err := pc.ReadFrom(buf)
+		if err != nil {
+			break
+		}
+		n.recordHit(ctx, trap, addr.String(), "", buf[:read])
+		pc.WriteTo([]byte(generateDecoyResponse(trap.Persona)), addr)
+	}
+	n.deactivate(trap)
+}
+
+func (n *Neuron) listenTLS(ctx context.Context, trap *Trap) {
+	cert, err := selfSignedCert(trap.SNI)
+	if err != nil {
+		n.deactivate(trap)
+		return
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+
+	ln, err := tls.Listen(networkFor("tcp", trap.Family), addrFor(trap.Family, trap.Port), cfg)
+	if err != nil {
+		n.deactivate(trap)
+		return
+	}
+	defer ln.Close()
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			break
+		}
+		tlsConn := conn.(*tls.Conn)
+		tlsConn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		tlsConn.Handshake() // populates ConnectionState().ServerName from the client's SNI
+
+		n.recordHit(ctx, trap, conn.RemoteAddr().String(), tlsConn.ConnectionState().ServerName, readProbe(conn))
+		conn.Write([]byte(generateDecoyResponse(trap.Persona)))
 		conn.Close()
 	}
-	trap.Active = false
+	n.deactivate(trap)
+}
+
+func (n *Neuron) listenHTTP(ctx context.Context, trap *Trap) {
+	ln, err := net.Listen(networkFor("tcp", trap.Family), addrFor(trap.Family, trap.Port))
+	if err != nil {
+		n.deactivate(trap)
+		return
+	}
+	defer ln.Close()
+
+	go func() {
+		<-ctx.Done()
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			break
+		}
+		conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+		req, _ := http.ReadRequest(bufio.NewReader(conn))
+		payload := ""
+		if req != nil {
+			payload = req.Method + " " + req.URL.String()
+		}
+		n.recordHit(ctx, trap, conn.RemoteAddr().String(), "", []byte(payload))
+		conn.Write([]byte(httpDecoyResponse(trap.Persona)))
+		conn.Close()
+	}
+	n.deactivate(trap)
+}
+
+// readProbe grabs whatever the other side sends within a short window so
+// the hit log can record it, without blocking a trap forever on a client
+// that connects and then just sits there.
+func readProbe(conn net.Conn) []byte {
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	buf := make([]byte, hitPayloadCap)
+	read, _ := conn.Read(buf)
+	return buf[:read]
 }
 
-func (n *Neuron) generateDecoyResponse() string {
+func generateDecoyResponse(persona string) string {
+	switch strings.ToLower(persona) {
+	case "nginx":
+		return "HTTP/1.1 401 Unauthorized\r\nServer: nginx/1.18.0\r\n\r\n"
+	case "iis":
+		return "HTTP/1.1 401 Unauthorized\r\nServer: Microsoft-IIS/10.0\r\n\r\n"
+	case "apache":
+		return "HTTP/1.1 401 Unauthorized\r\nServer: Apache/2.4.41 (Ubuntu)\r\n\r\n"
+	}
+
 	responses := []string{
 		"SSH-2.0-OpenSSH_8.2p1 Ubuntu-4ubuntu0.5\n",
 		"220 mail.internal.corp ESMTP Postfix\n",
 		"HTTP/1.1 401 Unauthorized\r\nWWW-Authenticate: Basic realm=\"Admin\"\r\n\r\n",
 		"MySQL 8.0.28\n\xff\x00\x00",
 	}
-	return responses[rand.Intn(len(responses))]
+	return responses[mathrand.Intn(len(responses))]
+}
+
+// httpDecoyResponse builds a realistic 401/500 response with the Server:
+// banner matching persona ("nginx", "iis", "apache"; anything else falls
+// back to nginx, the most common default).
+func httpDecoyResponse(persona string) string {
+	server := "nginx/1.18.0"
+	switch strings.ToLower(persona) {
+	case "iis":
+		server = "Microsoft-IIS/10.0"
+	case "apache":
+		server = "Apache/2.4.41 (Ubuntu)"
+	}
+
+	status, body := "401 Unauthorized", "401 Authorization Required"
+	if mathrand.Intn(4) == 0 {
+		status, body = "500 Internal Server Error", "500 Internal Server Error"
+	}
+
+	return fmt.Sprintf(
+		"HTTP/1.1 %s\r\nServer: %s\r\nContent-Length: %d\r\nConnection: close\r\n\r\n%s",
+		status, server, len(body), body,
+	)
+}
+
+// selfSignedCert mints an ephemeral self-signed certificate for a TLS trap,
+// with CN/SNI set to sni (or a generic fallback when the deploy didn't
+// specify one) so the handshake looks like it's terminating on a real host.
+func selfSignedCert(sni string) (tls.Certificate, error) {
+	if sni == "" {
+		sni = "localhost"
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("canary: generate key: %w", err)
+	}
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(0).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("canary: generate serial: %w", err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: sni},
+		DNSNames:     []string{sni},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("canary: create certificate: %w", err)
+	}
+
+	return tls.Certificate{Certificate: [][]byte{der}, PrivateKey: key}, nil
 }
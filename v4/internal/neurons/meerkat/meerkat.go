@@ -3,6 +3,7 @@ package meerkat
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
@@ -12,21 +13,33 @@ import (
 	"github.com/Alartist40/cynapse/internal/core"
 )
 
+// defaultProbeFile is where the data-driven service-detection signature
+// table lives, editable without a rebuild. See probes.go.
+const defaultProbeFile = "./data/meerkat/probes.yaml"
+
 // ScanResult holds the result for a single port.
 type ScanResult struct {
-	Port   int    `json:"port"`
-	State  string `json:"state"` // "open", "closed", "filtered"
-	Banner string `json:"banner,omitempty"`
+	Port    int    `json:"port"`
+	State   string `json:"state"` // "open", "closed", "filtered"
+	Banner  string `json:"banner,omitempty"`
+	Service string `json:"service,omitempty"`
+	Product string `json:"product,omitempty"`
+	Version string `json:"version,omitempty"`
+	CPE     string `json:"cpe,omitempty"`
 }
 
 // Neuron implements the Meerkat network scanner neuron.
 type Neuron struct {
 	timeout time.Duration
 	workers int
+
+	probeFile  string
+	probeOnce  sync.Once
+	probeTable *ProbeTable
 }
 
 func New() *Neuron {
-	return &Neuron{timeout: 2 * time.Second, workers: 100}
+	return &Neuron{timeout: 2 * time.Second, workers: 100, probeFile: defaultProbeFile}
 }
 
 func (n *Neuron) ID() string             { return "meerkat" }
@@ -91,6 +104,57 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 			Output:  fmt.Sprintf("⚡ Quick scan of %s:\n%s", target, strings.Join(lines, "\n")),
 		}, nil
 
+	case "service_detect":
+		target := task.Params["target"]
+		if target == "" {
+			target = "127.0.0.1"
+		}
+		var ports []int
+		for _, s := range strings.Split(task.Params["ports"], ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			var p int
+			if _, err := fmt.Sscanf(s, "%d", &p); err == nil {
+				ports = append(ports, p)
+			}
+		}
+		if len(ports) == 0 {
+			return core.Result{}, fmt.Errorf("meerkat: service_detect requires a non-empty \"ports\" param")
+		}
+
+		results := n.scanSpecificPorts(ctx, target, ports)
+
+		var lines []string
+		for _, r := range results {
+			if r.State != "open" {
+				continue
+			}
+			line := fmt.Sprintf("  %d/tcp  OPEN", r.Port)
+			switch {
+			case r.Product != "" && r.Version != "":
+				line += fmt.Sprintf("  %s %s", r.Product, r.Version)
+			case r.Product != "":
+				line += "  " + r.Product
+			case r.Service != "":
+				line += "  " + r.Service
+			}
+			lines = append(lines, line)
+		}
+
+		data, err := json.Marshal(results)
+		if err != nil {
+			return core.Result{}, fmt.Errorf("meerkat: encode service_detect results: %w", err)
+		}
+
+		return core.Result{
+			Success: true,
+			Output:  fmt.Sprintf("🔍 Service detection on %s:\n%s", target, strings.Join(lines, "\n")),
+			Data:    data,
+			Details: map[string]string{"target": target},
+		}, nil
+
 	default:
 		return core.Result{}, fmt.Errorf("meerkat: unknown operation %s", task.Operation)
 	}
@@ -153,16 +217,16 @@ func (n *Neuron) probePort(target string, port int) ScanResult {
 	if err != nil {
 		return ScanResult{Port: port, State: "closed"}
 	}
-	defer conn.Close()
-
-	// Try to grab banner
-	banner := ""
-	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
-	buf := make([]byte, 256)
-	n2, err := conn.Read(buf)
-	if err == nil && n2 > 0 {
-		banner = strings.TrimSpace(string(buf[:n2]))
+	conn.Close()
+
+	banner, info := n.detectService(target, port)
+	return ScanResult{
+		Port:    port,
+		State:   "open",
+		Banner:  banner,
+		Service: info.Service,
+		Product: info.Product,
+		Version: info.Version,
+		CPE:     info.CPE,
 	}
-
-	return ScanResult{Port: port, State: "open", Banner: banner}
 }
@@ -0,0 +1,245 @@
+package meerkat
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ServiceInfo is what service detection manages to pull out of a probe
+// response. Any field may be empty if the signature table didn't have a
+// match precise enough to fill it in.
+type ServiceInfo struct {
+	Service string `json:"service,omitempty"`
+	Product string `json:"product,omitempty"`
+	Version string `json:"version,omitempty"`
+	CPE     string `json:"cpe,omitempty"`
+}
+
+// ProbeSpec is one entry under probes.yaml's "probes" key: what to send to a
+// set of ports once connected.
+type ProbeSpec struct {
+	Ports []int
+	Send  string
+	TLS   bool
+}
+
+// signature is one entry under probes.yaml's "signatures" key, with its
+// pattern pre-compiled.
+type signature struct {
+	re      *regexp.Regexp
+	service string
+	product string
+	cpe     string
+}
+
+// ProbeTable is the parsed contents of probes.yaml.
+type ProbeTable struct {
+	probes     []ProbeSpec
+	signatures []signature
+}
+
+// specForPort returns the probe spec that applies to port, if any.
+func (t *ProbeTable) specForPort(port int) (ProbeSpec, bool) {
+	for _, spec := range t.probes {
+		for _, p := range spec.Ports {
+			if p == port {
+				return spec, true
+			}
+		}
+	}
+	return ProbeSpec{}, false
+}
+
+// match runs every signature against response and returns the first hit.
+func (t *ProbeTable) match(response []byte) ServiceInfo {
+	for _, sig := range t.signatures {
+		m := sig.re.FindSubmatch(response)
+		if m == nil {
+			continue
+		}
+		info := ServiceInfo{Service: sig.service, Product: sig.product}
+		for i, name := range sig.re.SubexpNames() {
+			if name == "version" && i < len(m) {
+				info.Version = string(m[i])
+			}
+		}
+		info.CPE = strings.ReplaceAll(sig.cpe, "{version}", info.Version)
+		return info
+	}
+	return ServiceInfo{}
+}
+
+// loadProbeTable loads and parses a probes.yaml file. It is called at most
+// once per Neuron (see Neuron.probeTable) and a read failure just leaves
+// service detection disabled — raw banner grabbing still works.
+func loadProbeTable(path string) (*ProbeTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := parseProbeYAML(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+
+	table := &ProbeTable{}
+	for _, item := range raw.probes {
+		var ports []int
+		for _, s := range strings.Split(strings.Trim(item["ports"], "[]"), ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			p, err := strconv.Atoi(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s: probe has invalid port %q: %w", path, s, err)
+			}
+			ports = append(ports, p)
+		}
+		table.probes = append(table.probes, ProbeSpec{
+			Ports: ports,
+			Send:  item["send"],
+			TLS:   item["tls"] == "true",
+		})
+	}
+	for _, item := range raw.signatures {
+		re, err := regexp.Compile(item["pattern"])
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid signature pattern %q: %w", path, item["pattern"], err)
+		}
+		table.signatures = append(table.signatures, signature{
+			re:      re,
+			service: item["service"],
+			product: item["product"],
+			cpe:     item["cpe"],
+		})
+	}
+	return table, nil
+}
+
+// loadedProbeTable lazily loads and caches the neuron's probe table from n.probeFile.
+func (n *Neuron) loadedProbeTable() *ProbeTable {
+	n.probeOnce.Do(func() {
+		table, err := loadProbeTable(n.probeFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "meerkat: service detection disabled: %v\n", err)
+			table = &ProbeTable{}
+		}
+		n.probeTable = table
+	})
+	return n.probeTable
+}
+
+// detectService connects to target:port, runs whatever probe the table has
+// for that port (plain or TLS), and matches the response against the
+// signature table. The raw banner is always returned alongside whatever
+// ServiceInfo was recognized, even if it's empty.
+func (n *Neuron) detectService(target string, port int) (banner string, info ServiceInfo) {
+	table := n.loadedProbeTable()
+	spec, ok := table.specForPort(port)
+
+	addr := net.JoinHostPort(target, strconv.Itoa(port))
+	var conn net.Conn
+	var err error
+	if ok && spec.TLS {
+		d := &net.Dialer{Timeout: n.timeout}
+		conn, err = tls.DialWithDialer(d, "tcp", addr, &tls.Config{InsecureSkipVerify: true})
+	} else {
+		conn, err = net.DialTimeout("tcp", addr, n.timeout)
+	}
+	if err != nil {
+		return "", ServiceInfo{}
+	}
+	defer conn.Close()
+
+	if ok && spec.Send != "" {
+		conn.SetWriteDeadline(time.Now().Add(n.timeout))
+		conn.Write([]byte(spec.Send))
+	}
+
+	conn.SetReadDeadline(time.Now().Add(500 * time.Millisecond))
+	buf := make([]byte, 4096)
+	read, _ := conn.Read(buf)
+	if read <= 0 {
+		return "", ServiceInfo{}
+	}
+
+	response := buf[:read]
+	return strings.TrimSpace(string(response)), table.match(response)
+}
+
+// rawProbeFile is the result of parseProbeYAML: each top-level list
+// flattened into its items' raw string fields, before ports/regexes/etc.
+// get parsed out of them.
+type rawProbeFile struct {
+	probes     []map[string]string
+	signatures []map[string]string
+}
+
+// parseProbeYAML parses the narrow YAML subset probes.yaml is written in:
+// two top-level keys ("probes", "signatures"), each a list of flat maps,
+// with scalar values either bare words, "[bracketed, lists]", or
+// "double-quoted strings" using Go escape syntax. It is a purpose-built
+// reader for exactly that shape rather than a general YAML parser.
+func parseProbeYAML(data []byte) (rawProbeFile, error) {
+	var out rawProbeFile
+	var section string
+	var item map[string]string
+
+	flush := func() {
+		if item == nil {
+			return
+		}
+		switch section {
+		case "probes":
+			out.probes = append(out.probes, item)
+		case "signatures":
+			out.signatures = append(out.signatures, item)
+		}
+		item = nil
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if !strings.HasPrefix(line, " ") && strings.HasSuffix(trimmed, ":") {
+			flush()
+			section = strings.TrimSuffix(trimmed, ":")
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- ") {
+			flush()
+			item = map[string]string{}
+			trimmed = strings.TrimPrefix(trimmed, "- ")
+		}
+
+		if item == nil {
+			continue
+		}
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			value = unquoted
+		}
+		item[key] = value
+	}
+	flush()
+
+	return out, nil
+}
@@ -0,0 +1,219 @@
+package wolverine
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// entropyThresholds maps a finding category to the minimum Shannon entropy
+// (bits/char) a candidate token must clear before it's reported. Regex
+// "secrets" matches need less entropy than base64-ish ones, which tend to
+// be denser; "generic" is the fallback for any other category and the
+// threshold of the entropy-first pass.
+var entropyThresholds = map[string]float64{
+	"secrets": 3.5,
+	"base64":  4.5,
+	"generic": 4.5,
+}
+
+// highConfidencePrefixes are regexes whose matches are trusted enough to be
+// optionally verified against the issuing service.
+var highConfidencePrefixes = map[string]*regexp.Regexp{
+	"aws":    regexp.MustCompile(`(?:AKIA|ABIA|ACCA|ASIA)[0-9A-Z]{16}`),
+	"github": regexp.MustCompile(`ghp_[0-9a-zA-Z]{36}`),
+	"openai": regexp.MustCompile(`sk-[0-9a-zA-Z]{48}`),
+}
+
+// tokenSplitRe tokenizes a line for the entropy-first pass.
+var tokenSplitRe = regexp.MustCompile(`[^A-Za-z0-9+/_\-=]+`)
+
+// shannonEntropy computes the Shannon entropy of s in bits per character.
+func shannonEntropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	freq := make(map[rune]int)
+	for _, r := range s {
+		freq[r]++
+	}
+	var entropy float64
+	n := float64(len(s))
+	for _, count := range freq {
+		p := float64(count) / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
+
+// Verifier makes a cheap authenticated call to confirm a high-confidence
+// secret is actually live, so findings can be triaged with confidence.
+type Verifier interface {
+	// Verify returns true if token is a currently valid credential.
+	Verify(ctx context.Context, token string) (bool, error)
+}
+
+// Allowlist suppresses known-safe matches, loaded from a .cynapseignore file
+// containing one path glob or token-hash-per-line.
+type Allowlist struct {
+	globs      []string
+	tokenHashes map[string]bool
+}
+
+// LoadAllowlist reads globs/hashes from path. A missing file yields an empty,
+// always-permissive allowlist rather than an error.
+func LoadAllowlist(path string) *Allowlist {
+	al := &Allowlist{tokenHashes: make(map[string]bool)}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return al
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if len(line) == 64 && isHex(line) {
+			al.tokenHashes[line] = true
+		} else {
+			al.globs = append(al.globs, line)
+		}
+	}
+	return al
+}
+
+func isHex(s string) bool {
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdefABCDEF", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// AllowsPath reports whether path matches a suppressed glob.
+func (al *Allowlist) AllowsPath(path string) bool {
+	if al == nil {
+		return false
+	}
+	for _, g := range al.globs {
+		if ok, _ := filepath.Match(g, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowsToken reports whether the SHA-256 of token was allowlisted.
+func (al *Allowlist) AllowsToken(token string) bool {
+	if al == nil {
+		return false
+	}
+	sum := sha256.Sum256([]byte(token))
+	return al.tokenHashes[hex.EncodeToString(sum[:])]
+}
+
+// entropyFindings runs the entropy-first pass over a file's lines: any
+// token of length >= 20 whose entropy clears the threshold is flagged,
+// independent of whether it matched a known regex.
+func entropyFindings(path string, lines []string, al *Allowlist) []Finding {
+	var findings []Finding
+	if al.AllowsPath(path) {
+		return nil
+	}
+	for i, line := range lines {
+		for _, tok := range tokenSplitRe.Split(line, -1) {
+			if len(tok) < 20 || al.AllowsToken(tok) {
+				continue
+			}
+			ent := shannonEntropy(tok)
+			if ent < entropyThresholds["generic"] {
+				continue
+			}
+			findings = append(findings, Finding{
+				Severity: "low",
+				Category: "entropy",
+				Message:  "High-entropy token (possible secret)",
+				File:     path,
+				Line:     i + 1,
+			})
+		}
+	}
+	return findings
+}
+
+// entropyFilter suppresses regex-matched candidates whose extracted value
+// doesn't clear the category's entropy threshold, cutting down on false
+// positives like `api_key = "example"`.
+func entropyFilter(category, candidate string) bool {
+	threshold, ok := entropyThresholds[category]
+	if !ok {
+		threshold = entropyThresholds["generic"]
+	}
+	return shannonEntropy(candidate) >= threshold
+}
+
+// classifyPrefix returns the high-confidence prefix name for a token, if any.
+func classifyPrefix(token string) (string, bool) {
+	for name, re := range highConfidencePrefixes {
+		if re.MatchString(token) {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// walkDirParallel walks dir with a bounded worker pool, invoking fn for
+// every regular, text, size-bounded file. It replaces the single-threaded
+// filepath.Walk used by auditDirectory/searchSecrets.
+func walkDirParallel(ctx context.Context, dir string, workers int, fn func(path string)) {
+	if workers <= 0 {
+		workers = 8
+	}
+	paths := make(chan string, workers*2)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range paths {
+				select {
+				case <-ctx.Done():
+					continue
+				default:
+				}
+				fn(path)
+			}
+		}()
+	}
+
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() || info.Size() > 1<<20 {
+			return nil
+		}
+		if !isTextFile(filepath.Ext(path)) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case paths <- path:
+		}
+		return nil
+	})
+
+	close(paths)
+	wg.Wait()
+}
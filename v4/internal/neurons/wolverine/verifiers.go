@@ -0,0 +1,56 @@
+package wolverine
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// GitHubVerifier confirms a ghp_ token is live via GET /user.
+type GitHubVerifier struct {
+	Client *http.Client
+}
+
+// NewGitHubVerifier creates a verifier with a short-timeout HTTP client.
+func NewGitHubVerifier() *GitHubVerifier {
+	return &GitHubVerifier{Client: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// Verify implements Verifier.
+func (v *GitHubVerifier) Verify(ctx context.Context, token string) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/user", nil)
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Authorization", "token "+token)
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	return resp.StatusCode == http.StatusOK, nil
+}
+
+// AWSVerifier is registered for AKIA-style access keys but makes no STS
+// call and reads no secret key: its Verify always returns false with a nil
+// error, so with verification enabled every AWS match is reported as "low".
+type AWSVerifier struct {
+	Client *http.Client
+	Region string
+}
+
+// NewAWSVerifier creates a verifier targeting the given region (defaults to
+// us-east-1).
+func NewAWSVerifier(region string) *AWSVerifier {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &AWSVerifier{Client: &http.Client{Timeout: 5 * time.Second}, Region: region}
+}
+
+// Verify implements Verifier. It sends no request and always returns
+// false, nil; Client and Region are not used.
+func (v *AWSVerifier) Verify(ctx context.Context, accessKeyID string) (bool, error) {
+	return false, nil
+}
@@ -5,10 +5,12 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/Alartist40/cynapse/internal/alerts"
 	"github.com/Alartist40/cynapse/internal/core"
 )
 
@@ -23,32 +25,60 @@ type Finding struct {
 
 // Neuron implements the Wolverine RAG audit neuron.
 type Neuron struct {
-	patterns map[string][]*regexp.Regexp
+	patterns  map[string][]*regexp.Regexp
+	allowlist *Allowlist
+	verify    bool
+	verifiers map[string]Verifier
+	workers   int
+	alertSink alerts.Sink
 }
 
 func New() *Neuron {
 	n := &Neuron{
-		patterns: make(map[string][]*regexp.Regexp),
+		patterns:  make(map[string][]*regexp.Regexp),
+		allowlist: LoadAllowlist(".cynapseignore"),
+		verifiers: map[string]Verifier{
+			"github": NewGitHubVerifier(),
+			"aws":    NewAWSVerifier(""),
+		},
+		workers: 8,
 	}
 	n.loadPatterns()
 	return n
 }
 
+// SetVerify toggles whether high-confidence matches are confirmed against
+// the issuing service. Execute turns it on when a task carries
+// verify=true; it is off by default since it makes live network calls.
+func (n *Neuron) SetVerify(enabled bool) {
+	n.verify = enabled
+}
+
+// SetAlertSink wires an alerts.Sink in, satisfying alerts.Emitter so
+// hivemind.Engine.RegisterNeuron can hook this up automatically.
+func (n *Neuron) SetAlertSink(sink alerts.Sink) {
+	n.alertSink = sink
+}
+
 func (n *Neuron) ID() string             { return "wolverine" }
 func (n *Neuron) Name() string           { return "Wolverine — RAG Security Audit" }
 func (n *Neuron) Capabilities() []string { return []string{"audit_file", "audit_dir", "search_secrets", "analyze_logs"} }
 
 func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, error) {
+	if task.Params["verify"] == "true" {
+		n.SetVerify(true)
+	}
+
 	switch task.Operation {
 	case "audit_file":
 		path := task.Params["path"]
 		findings := n.auditFile(path)
-		return n.formatFindings(findings), nil
+		return n.formatFindings(ctx, findings), nil
 
 	case "audit_dir":
 		dir := task.Params["dir"]
 		findings := n.auditDirectory(ctx, dir)
-		return n.formatFindings(findings), nil
+		return n.formatFindings(ctx, findings), nil
 
 	case "search_secrets":
 		dir := task.Params["dir"]
@@ -56,7 +86,7 @@ func (n *Neuron) Execute(ctx context.Context, task core.Task) (core.Result, erro
 			dir = "."
 		}
 		findings := n.searchSecrets(ctx, dir)
-		return n.formatFindings(findings), nil
+		return n.formatFindings(ctx, findings), nil
 
 	case "analyze_logs":
 		path := task.Params["path"]
@@ -91,6 +121,10 @@ func (n *Neuron) loadPatterns() {
 }
 
 func (n *Neuron) auditFile(path string) []Finding {
+	if n.allowlist.AllowsPath(path) {
+		return nil
+	}
+
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return []Finding{{Severity: "info", Category: "error", Message: fmt.Sprintf("Cannot read: %v", err)}}
@@ -98,97 +132,131 @@ func (n *Neuron) auditFile(path string) []Finding {
 
 	var findings []Finding
 	lines := strings.Split(string(data), "\n")
+	regexLines := make(map[int]bool) // lines already flagged by a regex match
 
 	for i, line := range lines {
 		for cat, patterns := range n.patterns {
 			for _, p := range patterns {
-				if p.MatchString(line) {
-					sev := "medium"
-					if cat == "secrets" {
-						sev = "critical"
-					}
-					findings = append(findings, Finding{
-						Severity: sev,
-						Category: cat,
-						Message:  fmt.Sprintf("Pattern match: %s", p.String()),
-						File:     path,
-						Line:     i + 1,
-					})
+				m := p.FindString(line)
+				if m == "" {
+					continue
+				}
+				if n.allowlist.AllowsToken(m) {
+					continue
+				}
+				if cat == "secrets" && !entropyFilter(cat, m) {
+					// Matched a known prefix/shape but the value itself looks
+					// like a placeholder (e.g. `api_key = "example"`).
+					continue
 				}
+
+				sev := "medium"
+				if cat == "secrets" {
+					sev = "critical"
+				}
+				if prefix, ok := classifyPrefix(m); ok {
+					sev = n.verifiedSeverity(prefix, m, sev)
+				}
+
+				findings = append(findings, Finding{
+					Severity: sev,
+					Category: cat,
+					Message:  fmt.Sprintf("Pattern match: %s", p.String()),
+					File:     path,
+					Line:     i + 1,
+				})
+				regexLines[i] = true
 			}
 		}
 	}
 
+	for _, f := range entropyFindings(path, lines, n.allowlist) {
+		if !regexLines[f.Line-1] {
+			findings = append(findings, f)
+		}
+	}
+
 	return findings
 }
 
+// verifiedSeverity promotes a high-confidence match to "critical" when a
+// live Verifier confirms the credential, or demotes it to "low" when
+// verification is enabled but fails. With verification off it keeps fallback.
+func (n *Neuron) verifiedSeverity(prefix, token, fallback string) string {
+	if !n.verify {
+		return fallback
+	}
+	v, ok := n.verifiers[prefix]
+	if !ok {
+		return fallback
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ok, err := v.Verify(ctx, token)
+	if err != nil || !ok {
+		return "low"
+	}
+	return "critical"
+}
+
 func (n *Neuron) auditDirectory(ctx context.Context, dir string) []Finding {
+	var mu sync.Mutex
 	var allFindings []Finding
 
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
-			return nil
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		// Skip binary and large files
-		if info.Size() > 1<<20 { // 1MB
-			return nil
-		}
-		ext := filepath.Ext(path)
-		if !isTextFile(ext) {
-			return nil
-		}
-
+	walkDirParallel(ctx, dir, n.workers, func(path string) {
 		findings := n.auditFile(path)
+		if len(findings) == 0 {
+			return
+		}
+		mu.Lock()
 		allFindings = append(allFindings, findings...)
-		return nil
+		mu.Unlock()
 	})
 
 	return allFindings
 }
 
 func (n *Neuron) searchSecrets(ctx context.Context, dir string) []Finding {
+	var mu sync.Mutex
 	var findings []Finding
 
-	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() || info.Size() > 1<<20 {
-			return nil
-		}
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if !isTextFile(filepath.Ext(path)) {
-			return nil
+	walkDirParallel(ctx, dir, n.workers, func(path string) {
+		if n.allowlist.AllowsPath(path) {
+			return
 		}
-
 		data, err := os.ReadFile(path)
 		if err != nil {
-			return nil
+			return
 		}
 
 		lines := strings.Split(string(data), "\n")
+		var local []Finding
 		for i, line := range lines {
 			for _, p := range n.patterns["secrets"] {
-				if p.MatchString(line) {
-					findings = append(findings, Finding{
-						Severity: "critical",
-						Category: "secret",
-						Message:  "Potential secret/credential exposed",
-						File:     path,
-						Line:     i + 1,
-					})
+				m := p.FindString(line)
+				if m == "" || n.allowlist.AllowsToken(m) || !entropyFilter("secrets", m) {
+					continue
 				}
+				sev := "critical"
+				if prefix, ok := classifyPrefix(m); ok {
+					sev = n.verifiedSeverity(prefix, m, sev)
+				}
+				local = append(local, Finding{
+					Severity: sev,
+					Category: "secret",
+					Message:  "Potential secret/credential exposed",
+					File:     path,
+					Line:     i + 1,
+				})
 			}
 		}
-		return nil
+		local = append(local, entropyFindings(path, lines, n.allowlist)...)
+		if len(local) == 0 {
+			return
+		}
+		mu.Lock()
+		findings = append(findings, local...)
+		mu.Unlock()
 	})
 
 	return findings
@@ -217,7 +285,36 @@ func (n *Neuron) analyzeLogs(path string) string {
 		path, len(lines), errors, warnings)
 }
 
-func (n *Neuron) formatFindings(findings []Finding) core.Result {
+// emitFindingAlert pages a human about critical/high findings — the ones
+// that shouldn't wait for someone to think to check the TUI.
+func (n *Neuron) emitFindingAlert(ctx context.Context, f Finding) {
+	if n.alertSink == nil {
+		return
+	}
+	var severity alerts.Severity
+	switch f.Severity {
+	case "critical":
+		severity = alerts.SeverityCritical
+	case "high":
+		severity = alerts.SeverityHigh
+	default:
+		return
+	}
+
+	details := map[string]string{"category": f.Category}
+	if f.File != "" {
+		details["file"] = f.File
+		details["line"] = fmt.Sprintf("%d", f.Line)
+	}
+	n.alertSink.Emit(ctx, alerts.Alert{
+		Severity: severity,
+		Source:   n.ID(),
+		Message:  f.Message,
+		Details:  details,
+	})
+}
+
+func (n *Neuron) formatFindings(ctx context.Context, findings []Finding) core.Result {
 	if len(findings) == 0 {
 		return core.Result{Success: true, Output: "✅ No security issues found."}
 	}
@@ -245,6 +342,8 @@ func (n *Neuron) formatFindings(findings []Finding) core.Result {
 			loc = fmt.Sprintf(" (%s:%d)", f.File, f.Line)
 		}
 		lines = append(lines, fmt.Sprintf("  %s [%s] %s%s", icon, strings.ToUpper(f.Severity), f.Message, loc))
+
+		n.emitFindingAlert(ctx, f)
 	}
 
 	summary := fmt.Sprintf("🐺 Audit: %d findings (🔴%d 🟠%d 🟡%d 🟢%d)\n\n%s",
@@ -0,0 +1,194 @@
+// Package store persists TUI conversations across restarts in SQLite, the
+// same database/sql + mattn/go-sqlite3 combination hivemind.Engine already
+// uses for workflow instances.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Conversation is a persisted chat thread, identified by an opaque ID that
+// doubles as tui.Model's activeThread key.
+type Conversation struct {
+	ID        string
+	Title     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// Message is a single persisted chat message within a Conversation.
+//
+// Messages form a DAG rather than a flat list: ParentID points at the
+// message this one replies to (0 for the first message in a
+// conversation), and BranchID is this message's ordinal among its
+// parent's children. Editing and resending an earlier user message
+// creates a sibling with a new BranchID instead of overwriting history,
+// so every edit is a branch point a caller can navigate back to.
+type Message struct {
+	ID       int64
+	ParentID int64
+	BranchID int
+	Role     string
+	Content  string
+	Time     time.Time
+}
+
+// Store persists conversations and their messages to SQLite.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the SQLite database at path and
+// ensures its schema exists.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("store: open %s: %w", path, err)
+	}
+	s := &Store{db: db}
+	if err := s.initSchema(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Store) initSchema() error {
+	schema := `
+	CREATE TABLE IF NOT EXISTS conversations (
+		id TEXT PRIMARY KEY,
+		title TEXT,
+		created_at REAL,
+		updated_at REAL
+	);
+	CREATE TABLE IF NOT EXISTS messages (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		conversation_id TEXT NOT NULL,
+		parent_id INTEGER NOT NULL DEFAULT 0,
+		branch_id INTEGER NOT NULL DEFAULT 0,
+		role TEXT,
+		content TEXT,
+		time REAL
+	);`
+	_, err := s.db.Exec(schema)
+	return err
+}
+
+// CreateConversation inserts a new, empty conversation and returns it.
+func (s *Store) CreateConversation(title string) (Conversation, error) {
+	now := time.Now()
+	id := fmt.Sprintf("conv_%d", now.UnixNano())
+	_, err := s.db.Exec(
+		"INSERT INTO conversations (id, title, created_at, updated_at) VALUES (?, ?, ?, ?)",
+		id, title, float64(now.Unix()), float64(now.Unix()),
+	)
+	if err != nil {
+		return Conversation{}, fmt.Errorf("store: create conversation: %w", err)
+	}
+	return Conversation{ID: id, Title: title, CreatedAt: now, UpdatedAt: now}, nil
+}
+
+// ListConversations returns every conversation, most recently updated first.
+func (s *Store) ListConversations() ([]Conversation, error) {
+	rows, err := s.db.Query("SELECT id, title, created_at, updated_at FROM conversations ORDER BY updated_at DESC")
+	if err != nil {
+		return nil, fmt.Errorf("store: list conversations: %w", err)
+	}
+	defer rows.Close()
+
+	var convs []Conversation
+	for rows.Next() {
+		var c Conversation
+		var created, updated float64
+		if err := rows.Scan(&c.ID, &c.Title, &created, &updated); err != nil {
+			return nil, fmt.Errorf("store: scan conversation: %w", err)
+		}
+		c.CreatedAt = time.Unix(int64(created), 0)
+		c.UpdatedAt = time.Unix(int64(updated), 0)
+		convs = append(convs, c)
+	}
+	return convs, rows.Err()
+}
+
+// AppendMessage persists msg to conversationID, bumps the conversation's
+// updated_at so ListConversations reflects the latest activity first, and
+// returns the row ID assigned to msg so a caller can use it as a ParentID
+// for whatever comes next.
+func (s *Store) AppendMessage(conversationID string, msg Message) (int64, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("store: append message: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(
+		"INSERT INTO messages (conversation_id, parent_id, branch_id, role, content, time) VALUES (?, ?, ?, ?, ?, ?)",
+		conversationID, msg.ParentID, msg.BranchID, msg.Role, msg.Content, float64(msg.Time.Unix()),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("store: append message: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("store: append message: %w", err)
+	}
+	if _, err := tx.Exec(
+		"UPDATE conversations SET updated_at = ? WHERE id = ?",
+		float64(msg.Time.Unix()), conversationID,
+	); err != nil {
+		return 0, fmt.Errorf("store: append message: %w", err)
+	}
+	return id, tx.Commit()
+}
+
+// LoadMessages returns every message in a conversation — every branch, not
+// just the active one — oldest first. Callers reconstruct whichever path
+// through the DAG they want to display.
+func (s *Store) LoadMessages(conversationID string) ([]Message, error) {
+	rows, err := s.db.Query(
+		"SELECT id, parent_id, branch_id, role, content, time FROM messages WHERE conversation_id = ? ORDER BY id ASC",
+		conversationID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("store: load messages: %w", err)
+	}
+	defer rows.Close()
+
+	var msgs []Message
+	for rows.Next() {
+		var m Message
+		var t float64
+		if err := rows.Scan(&m.ID, &m.ParentID, &m.BranchID, &m.Role, &m.Content, &t); err != nil {
+			return nil, fmt.Errorf("store: scan message: %w", err)
+		}
+		m.Time = time.Unix(int64(t), 0)
+		msgs = append(msgs, m)
+	}
+	return msgs, rows.Err()
+}
+
+// DeleteConversation removes a conversation and all of its messages.
+func (s *Store) DeleteConversation(id string) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("store: delete conversation: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM messages WHERE conversation_id = ?", id); err != nil {
+		return fmt.Errorf("store: delete conversation: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM conversations WHERE id = ?", id); err != nil {
+		return fmt.Errorf("store: delete conversation: %w", err)
+	}
+	return tx.Commit()
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
@@ -1,14 +1,18 @@
 // Package techsupport implements the IT Mode self-modifying plugin system.
-// Modules are Go plugins or Python scripts executed in a sandbox.
+// Modules are Go plugins, Python scripts, or shell scripts executed in a
+// sandbox.
 package techsupport
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"plugin"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +29,26 @@ type ModuleInfo struct {
 	Capabilities []string `json:"capabilities"`
 	Type         string   `json:"type"` // "go_plugin", "python", "script"
 	EntryPoint   string   `json:"entry_point"`
+
+	// Timeout overrides the executor's default per-call timeout, e.g.
+	// "2m". Zero value falls back to the executor's default.
+	Timeout string `json:"timeout,omitempty"`
+	// MaxOutputBytes caps how much stdout/stderr is captured from
+	// executePython/executeScript; output past the cap is dropped and the
+	// module keeps running. Zero falls back to defaultMaxOutputBytes.
+	MaxOutputBytes int64 `json:"max_output_bytes,omitempty"`
+	// AllowedEnv whitelists additional environment variable names passed
+	// through to python/script modules beyond baseEnvAllowlist.
+	AllowedEnv []string `json:"allowed_env,omitempty"`
+}
+
+// CynapseModule is the contract a Go plugin's exported "CynapseModule"
+// symbol must satisfy. Plugins declare their own Capabilities() rather than
+// trusting the registry's index.json, since the manifest is enforced
+// against both before a call is allowed through.
+type CynapseModule interface {
+	Execute(ctx context.Context, op string, params map[string]string) (core.Result, error)
+	Capabilities() []string
 }
 
 // Registry manages IT support modules.
@@ -86,10 +110,32 @@ func (r *Registry) loadFromDisk() {
 	}
 }
 
+// defaultMaxOutputBytes bounds executePython/executeScript output when a
+// module doesn't declare its own MaxOutputBytes.
+const defaultMaxOutputBytes = 1 << 20 // 1 MiB
+
+// baseEnvAllowlist is the fixed set of environment variables every
+// python/script module inherits, regardless of the caller's own env or
+// ModuleInfo.AllowedEnv. It deliberately excludes anything a caller could
+// use to redirect the module's interpreter (PATH overrides, PYTHONPATH,
+// LD_PRELOAD, etc.) — those must come from AllowedEnv explicitly.
+var baseEnvAllowlist = []string{"PATH", "HOME", "LANG", "TZ"}
+
+// ProgressFunc receives incremental stdout/stderr lines as a module runs,
+// so a long diagnostic can surface progress instead of only its final
+// result. Callers that don't care about progress pass nil.
+type ProgressFunc func(line string)
+
 // Executor runs IT support modules in a sandbox.
 type Executor struct {
 	registry *Registry
 	timeout  time.Duration
+
+	// plugins caches *plugin.Plugin handles by EntryPoint, since
+	// plugin.Open re-executing a .so's init() on every call would be both
+	// slow and, for plugins with non-idempotent init, wrong.
+	plugins   map[string]*plugin.Plugin
+	pluginsMu sync.Mutex
 }
 
 // NewExecutor creates an executor backed by the given registry.
@@ -97,31 +143,115 @@ func NewExecutor(registry *Registry) *Executor {
 	return &Executor{
 		registry: registry,
 		timeout:  30 * time.Second,
+		plugins:  make(map[string]*plugin.Plugin),
 	}
 }
 
-// Execute runs a module operation.
+// Registry returns the module registry this executor runs against, so
+// callers (e.g. the TUI's tool palette) can list/look up modules without
+// threading a separate *Registry alongside the *Executor.
+func (e *Executor) Registry() *Registry {
+	return e.registry
+}
+
+// Execute runs a module operation, discarding any progress output. See
+// ExecuteProgress for a variant that streams it.
 func (e *Executor) Execute(ctx context.Context, moduleID, operation string, params map[string]string) (core.Result, error) {
+	return e.ExecuteProgress(ctx, moduleID, operation, params, nil)
+}
+
+// ExecuteProgress runs a module operation, invoking progress for each line
+// of output produced while it runs (python/script modules only — go_plugin
+// modules only ever report their final core.Result). progress may be nil.
+func (e *Executor) ExecuteProgress(ctx context.Context, moduleID, operation string, params map[string]string, progress ProgressFunc) (core.Result, error) {
 	mod, ok := e.registry.Get(moduleID)
 	if !ok {
 		return core.Result{}, fmt.Errorf("IT module %s not found", moduleID)
 	}
+	if !capabilityAllowed(mod, operation) {
+		return core.Result{}, fmt.Errorf("IT module %s does not declare capability %q", moduleID, operation)
+	}
 
-	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	timeout := e.timeout
+	if mod.Timeout != "" {
+		if d, err := time.ParseDuration(mod.Timeout); err == nil {
+			timeout = d
+		}
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	switch mod.Type {
+	case "go_plugin":
+		return e.executeGoPlugin(ctx, mod, operation, params)
 	case "python":
-		return e.executePython(ctx, mod, operation, params)
+		return e.executePython(ctx, mod, operation, params, progress)
 	case "script":
-		return e.executeScript(ctx, mod, operation, params)
+		return e.executeScript(ctx, mod, operation, params, progress)
 	default:
 		return core.Result{}, fmt.Errorf("unsupported module type: %s", mod.Type)
 	}
 }
 
-func (e *Executor) executePython(ctx context.Context, mod ModuleInfo, operation string, params map[string]string) (core.Result, error) {
-	// Build argument list
+// capabilityAllowed reports whether mod's manifest declares operation. An
+// empty Capabilities list is treated as "nothing allowed", not "anything
+// allowed" — a module must opt in.
+func capabilityAllowed(mod ModuleInfo, operation string) bool {
+	for _, c := range mod.Capabilities {
+		if c == operation {
+			return true
+		}
+	}
+	return false
+}
+
+func (e *Executor) executeGoPlugin(ctx context.Context, mod ModuleInfo, operation string, params map[string]string) (core.Result, error) {
+	p, err := e.loadPlugin(mod.EntryPoint)
+	if err != nil {
+		return core.Result{}, fmt.Errorf("loading plugin %s: %w", mod.ID, err)
+	}
+
+	sym, err := p.Lookup("CynapseModule")
+	if err != nil {
+		return core.Result{}, fmt.Errorf("plugin %s: missing exported CynapseModule symbol: %w", mod.ID, err)
+	}
+	cm, ok := sym.(CynapseModule)
+	if !ok {
+		return core.Result{}, fmt.Errorf("plugin %s: CynapseModule does not satisfy techsupport.CynapseModule", mod.ID)
+	}
+
+	// The manifest's Capabilities is what the registry and the TUI's tool
+	// schema advertise to neurons; the plugin's own Capabilities() is what
+	// it actually implements. Both must allow the operation.
+	allowed := false
+	for _, c := range cm.Capabilities() {
+		if c == operation {
+			allowed = true
+			break
+		}
+	}
+	if !allowed {
+		return core.Result{}, fmt.Errorf("plugin %s does not implement capability %q", mod.ID, operation)
+	}
+
+	return cm.Execute(ctx, operation, params)
+}
+
+func (e *Executor) loadPlugin(entryPoint string) (*plugin.Plugin, error) {
+	e.pluginsMu.Lock()
+	defer e.pluginsMu.Unlock()
+	if p, ok := e.plugins[entryPoint]; ok {
+		return p, nil
+	}
+	p, err := plugin.Open(entryPoint)
+	if err != nil {
+		return nil, err
+	}
+	e.plugins[entryPoint] = p
+	return p, nil
+}
+
+func (e *Executor) executePython(ctx context.Context, mod ModuleInfo, operation string, params map[string]string, progress ProgressFunc) (core.Result, error) {
 	args := []string{mod.EntryPoint, "--operation", operation}
 	for k, v := range params {
 		args = append(args, fmt.Sprintf("--%s", k), v)
@@ -129,32 +259,116 @@ func (e *Executor) executePython(ctx context.Context, mod ModuleInfo, operation
 
 	cmd := exec.CommandContext(ctx, "python3", args...)
 	cmd.Dir = filepath.Dir(mod.EntryPoint)
+	cmd.Env = sandboxEnv(mod)
 
-	out, err := cmd.CombinedOutput()
+	out, err := runSandboxed(cmd, maxOutputBytes(mod), progress)
 	if err != nil {
 		return core.Result{
 			Success: false,
-			Output:  fmt.Sprintf("Module %s failed: %v\n%s", mod.ID, err, string(out)),
+			Output:  fmt.Sprintf("Module %s failed: %v\n%s", mod.ID, err, out),
 		}, nil
 	}
 
 	return core.Result{
 		Success: true,
-		Output:  strings.TrimSpace(string(out)),
+		Output:  strings.TrimSpace(out),
 		Details: map[string]string{"module": mod.ID, "operation": operation},
 	}, nil
 }
 
-func (e *Executor) executeScript(ctx context.Context, mod ModuleInfo, operation string, params map[string]string) (core.Result, error) {
+func (e *Executor) executeScript(ctx context.Context, mod ModuleInfo, operation string, params map[string]string, progress ProgressFunc) (core.Result, error) {
 	cmd := exec.CommandContext(ctx, mod.EntryPoint, operation)
+	env := sandboxEnv(mod)
 	for k, v := range params {
-		cmd.Env = append(cmd.Env, fmt.Sprintf("IT_%s=%s", strings.ToUpper(k), v))
+		env = append(env, fmt.Sprintf("IT_%s=%s", strings.ToUpper(k), v))
 	}
+	cmd.Env = env
 
-	out, err := cmd.CombinedOutput()
+	out, err := runSandboxed(cmd, maxOutputBytes(mod), progress)
 	if err != nil {
-		return core.Result{Success: false, Output: string(out)}, nil
+		return core.Result{Success: false, Output: out}, nil
+	}
+
+	return core.Result{Success: true, Output: strings.TrimSpace(out)}, nil
+}
+
+func maxOutputBytes(mod ModuleInfo) int64 {
+	if mod.MaxOutputBytes > 0 {
+		return mod.MaxOutputBytes
+	}
+	return defaultMaxOutputBytes
+}
+
+// sandboxEnv builds the environment a python/script module runs under: the
+// fixed baseEnvAllowlist plus anything the module's own manifest
+// whitelists, read from the executor's environment — never the caller's
+// params, and never the full inherited os.Environ(), so a module can't
+// widen its own access by requesting an arbitrary PATH via params.
+func sandboxEnv(mod ModuleInfo) []string {
+	allow := make(map[string]bool, len(baseEnvAllowlist)+len(mod.AllowedEnv))
+	for _, k := range baseEnvAllowlist {
+		allow[k] = true
+	}
+	for _, k := range mod.AllowedEnv {
+		allow[k] = true
 	}
+	var env []string
+	for k := range allow {
+		if v, ok := os.LookupEnv(k); ok {
+			env = append(env, k+"="+v)
+		}
+	}
+	return env
+}
+
+// runSandboxed runs cmd with stdout and stderr streamed line-by-line
+// instead of buffered with CombinedOutput, so progress is invoked (when
+// non-nil) as output arrives rather than only once the module exits. It
+// still returns the full combined output, truncated at maxBytes so a
+// runaway module can't exhaust memory.
+func runSandboxed(cmd *exec.Cmd, maxBytes int64, progress ProgressFunc) (string, error) {
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return "", err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return "", err
+	}
+
+	var mu sync.Mutex
+	var buf strings.Builder
+	var total int64
+	var wg sync.WaitGroup
+
+	collect := func(r io.Reader) {
+		defer wg.Done()
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := scanner.Text()
+			mu.Lock()
+			if total < maxBytes {
+				buf.WriteString(line)
+				buf.WriteByte('\n')
+				total += int64(len(line)) + 1
+			}
+			mu.Unlock()
+			if progress != nil {
+				progress(line)
+			}
+		}
+	}
+
+	wg.Add(2)
+	go collect(stdout)
+	go collect(stderr)
+	wg.Wait()
 
-	return core.Result{Success: true, Output: strings.TrimSpace(string(out))}, nil
+	err = cmd.Wait()
+	return buf.String(), err
 }
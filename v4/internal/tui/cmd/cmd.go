@@ -0,0 +1,30 @@
+// Package cmd defines the small message bus the TUI's WindowManager routes
+// window-navigation messages through, modeled on neonmodem's wm package:
+// any View can request a window switch by returning a tea.Cmd that emits
+// one of these instead of reaching into the manager directly.
+package cmd
+
+// WinOpen asks the WindowManager to push Target onto the window stack and
+// make it active, creating and initializing a new instance of the view each
+// time.
+type WinOpen struct {
+	Target string
+}
+
+// WMCloseWin asks the WindowManager to pop the active window off the
+// stack, returning to whatever was active before it (ultimately "chat").
+type WMCloseWin struct{}
+
+// ConversationSelected asks the WindowManager to switch the chat view to
+// the given conversation and close the conversations window, since only
+// the manager holds both the chat view and the conversations view.
+type ConversationSelected struct {
+	ID string
+}
+
+// ConversationDeleted tells the WindowManager a conversation was removed
+// from the store, so it can drop the matching in-memory thread cache on
+// the chat view.
+type ConversationDeleted struct {
+	ID string
+}
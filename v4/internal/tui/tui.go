@@ -9,16 +9,26 @@ package tui
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/Alartist40/cynapse/internal/agents"
 	"github.com/Alartist40/cynapse/internal/core"
 	"github.com/Alartist40/cynapse/internal/hivemind"
-	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/Alartist40/cynapse/internal/store"
+	"github.com/Alartist40/cynapse/internal/tui/cmd"
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
 )
 
 // ----- Styles -----
@@ -49,6 +59,10 @@ var (
 			Foreground(lipgloss.Color("#E5C07B")).
 			Italic(true)
 
+	toolMsgStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#C678DD")).
+			Italic(true)
+
 	paletteStyle = lipgloss.NewStyle().
 			Border(lipgloss.RoundedBorder()).
 			BorderForeground(lipgloss.Color("#00FFAA")).
@@ -66,6 +80,10 @@ var (
 				Foreground(lipgloss.Color("#282C34")).
 				Background(lipgloss.Color("#00FFAA")).
 				Bold(true)
+
+	focusCursorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#00FFAA")).
+				Bold(true)
 )
 
 // ----- Messages -----
@@ -74,6 +92,14 @@ type Message struct {
 	Role    string // "user", "assistant", "system"
 	Content string
 	Time    time.Time
+
+	// ID/ParentID mirror the row in store once persisted (both 0 until
+	// then). BranchID is this message's ordinal among ParentID's
+	// children — used to render a "[branch N/M]" indicator when an
+	// earlier user message has been edited and resent.
+	ID       int64
+	ParentID int64
+	BranchID int
 }
 
 // tickMsg drives periodic updates (animation, status polling).
@@ -85,6 +111,67 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// msgResponseChunk carries one incremental piece of an in-progress
+// assistant reply, appended to the last message in m.messages.
+type msgResponseChunk struct {
+	content string
+}
+
+// msgResponseEnd signals that a streamed reply is complete and the
+// in-progress assistant message can be committed to the active thread.
+type msgResponseEnd struct{}
+
+// msgResponseError signals that a streamed reply failed mid-flight.
+type msgResponseError struct {
+	err error
+}
+
+// toolCall is a request to invoke an IT module, parsed out of a neuron's
+// reply when it contains a <tool_call>{"module":...}</tool_call> block.
+type toolCall struct {
+	Module    string            `json:"module"`
+	Operation string            `json:"operation"`
+	Params    map[string]string `json:"params"`
+}
+
+// msgToolResult carries the outcome of executing a toolCall, so Update
+// can append it as a "tool" role message and resume generation.
+type msgToolResult struct {
+	call   toolCall
+	output string
+	err    error
+}
+
+// msgToolProgress carries one line of stdout/stderr from a module still
+// running, surfaced as a "system" message so a long diagnostic shows
+// progress instead of going quiet until it exits.
+type msgToolProgress struct {
+	call    toolCall
+	content string
+}
+
+var toolCallPattern = regexp.MustCompile(`(?s)<tool_call>\s*(\{.*?\})\s*</tool_call>`)
+
+// maxToolLoopDepth bounds consecutive tool calls within a single user
+// turn so a neuron that keeps requesting tools can't loop forever.
+const maxToolLoopDepth = 3
+
+// parseToolCall extracts the first <tool_call>...</tool_call> block from
+// content, if any, returning the parsed call and content with the block
+// removed.
+func parseToolCall(content string) (toolCall, string, bool) {
+	loc := toolCallPattern.FindStringSubmatchIndex(content)
+	if loc == nil {
+		return toolCall{}, content, false
+	}
+	var call toolCall
+	if err := json.Unmarshal([]byte(content[loc[2]:loc[3]]), &call); err != nil {
+		return toolCall{}, content, false
+	}
+	rest := strings.TrimSpace(content[:loc[0]] + content[loc[1]:])
+	return call, rest, true
+}
+
 // ----- Command Palette -----
 
 type PaletteCommand struct {
@@ -97,11 +184,10 @@ var defaultCommands = []PaletteCommand{
 	{Name: "quit", Description: "Exit Cynapse", Action: "quit"},
 	{Name: "clear", Description: "Clear chat history", Action: "clear"},
 	{Name: "health", Description: "Run system health check", Action: "health"},
-	{Name: "agent researcher", Description: "Spawn Researcher agent", Action: "agent_researcher"},
-	{Name: "agent coder", Description: "Spawn Coder agent", Action: "agent_coder"},
 	{Name: "neurons", Description: "List available neurons", Action: "neurons"},
 	{Name: "it-mode", Description: "Enter IT Support mode", Action: "it_mode"},
 	{Name: "threads", Description: "Show active threads", Action: "threads"},
+	{Name: "conversations", Description: "List, switch, or delete saved conversations", Action: "conversations"},
 	{Name: "help", Description: "Show keyboard shortcuts", Action: "help"},
 }
 
@@ -110,14 +196,25 @@ var defaultCommands = []PaletteCommand{
 // Model is the top-level Bubble Tea model for the Cynapse TUI.
 type Model struct {
 	engine *hivemind.Engine
+	store  *store.Store
+
+	// Agents (loaded from agents.yaml, may be nil if none configured).
+	// activeAgent, when set, prepends its SystemPrompt to outgoing tasks
+	// and routes them to its Neuron instead of the default.
+	agents      *agents.Registry
+	activeAgent *agents.Agent
 
-	// Chat state
+	// Chat state. activeThread is a store.Conversation ID once a store is
+	// wired up, so threads persist across restarts instead of dying with
+	// the process.
 	messages     []Message
 	threads      map[string][]Message
 	activeThread string
 
-	// Input
-	textInput textinput.Model
+	// Input. A multi-line textarea rather than a single-line textinput so
+	// Enter can insert a newline; Ctrl+D/Alt+Enter send, Ctrl+E hands the
+	// draft off to $EDITOR for composing long prompts.
+	textArea textarea.Model
 
 	// Viewport for scrollable chat
 	viewport viewport.Model
@@ -128,40 +225,107 @@ type Model struct {
 	paletteMatches []PaletteCommand
 	paletteIndex   int
 
-	// Help overlay
-	showHelp bool
+	// Message focus/branch navigation (Ctrl+J). childrenOf indexes every
+	// message loaded for the active conversation by ParentID, oldest
+	// first, so switchSibling and the branch indicator don't need to
+	// round-trip to the store.
+	focusMode   bool
+	cursorIndex int
+	editingID   int64
+	childrenOf  map[int64][]store.Message
+
+	// Tool-calling loop (Ctrl+T toggles whether "tool" messages render
+	// expanded). toolLoopDepth counts consecutive tool calls within one
+	// user turn, capped by maxToolLoopDepth to avoid runaway loops.
+	showToolResults bool
+	toolLoopDepth   int
 
 	// Status
 	modelState string // "ready", "thinking", "executing"
 	width      int
 	height     int
 	ready      bool
+
+	// Streaming reply in flight, if any.
+	streaming  bool
+	streamSub  chan tea.Msg
+	stopSignal chan struct{}
+
+	// Tool call in flight, if any. Separate from streamSub since a tool
+	// call can run while the assistant reply that triggered it has already
+	// been committed (finishStream runs before runToolCall's result lands).
+	toolSub chan tea.Msg
+
+	// Markdown rendering for assistant/system messages. mdRenderer wraps
+	// at the current viewport width, so it's rebuilt on every
+	// WindowSizeMsg; messageCache avoids re-running glamour/chroma on
+	// every redraw for messages that haven't changed.
+	mdRenderer   *glamour.TermRenderer
+	messageCache map[messageCacheKey]string
 }
 
-// New creates the initial TUI model.
-func New(engine *hivemind.Engine) Model {
-	ti := textinput.New()
-	ti.Placeholder = "Message Cynapse... (/ for commands)"
-	ti.Focus()
-	ti.CharLimit = 2048
-	ti.Width = 76
+// messageCacheKey identifies one rendered-markdown cache entry: content
+// re-renders differently at different viewport widths, so both are part
+// of the key.
+type messageCacheKey struct {
+	content string
+	width   int
+}
 
-	return Model{
+// New creates the initial TUI model, resuming the most recently active
+// conversation from st (or starting a fresh one if st has none yet).
+// agentsReg may be nil if agents.yaml wasn't found — the palette then lists
+// no "agent" entries and /agent only reports that no agents are configured.
+func New(engine *hivemind.Engine, st *store.Store, agentsReg *agents.Registry) Model {
+	ta := textarea.New()
+	ta.Placeholder = "Message Cynapse... (/ for commands, Ctrl+E to open $EDITOR)"
+	ta.Focus()
+	ta.CharLimit = 8192
+	ta.ShowLineNumbers = false
+	ta.SetWidth(76)
+	ta.SetHeight(3)
+
+	m := Model{
 		engine:         engine,
+		store:          st,
+		agents:         agentsReg,
 		messages:       []Message{},
-		threads:        map[string][]Message{"main": {}},
-		activeThread:   "main",
-		textInput:      ti,
+		threads:        map[string][]Message{},
+		textArea:       ta,
 		showPalette:    false,
 		paletteMatches: defaultCommands,
 		modelState:     "ready",
+		messageCache:   map[messageCacheKey]string{},
 	}
+
+	if st != nil {
+		convs, err := st.ListConversations()
+		if err == nil && len(convs) == 0 {
+			conv, cerr := st.CreateConversation("New conversation")
+			if cerr == nil {
+				convs = []store.Conversation{conv}
+			}
+		}
+		if len(convs) > 0 {
+			m.openConversation(convs[0].ID)
+		}
+	}
+	if m.activeThread == "" {
+		m.activeThread = "main"
+		m.threads[m.activeThread] = []Message{}
+	}
+
+	return m
 }
 
+// ID satisfies View so the WindowManager can host Model as its permanent
+// "chat" window alongside the other, pluggable ones.
+func (m Model) ID() string { return "chat" }
+
 // Init starts the TUI with an initial welcome message and tick loop.
 func (m Model) Init() tea.Cmd {
 	return tea.Batch(
-		textinput.Blink,
+		textarea.Blink,
 		tickCmd(),
 		tea.EnterAltScreen,
 		func() tea.Msg {
@@ -179,7 +343,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		headerHeight := 3
-		inputHeight := 3
+		inputHeight := 4
 		statusHeight := 1
 		chatHeight := m.height - headerHeight - inputHeight - statusHeight - 2
 		if !m.ready {
@@ -190,38 +354,142 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.viewport.Width = m.width - 2
 			m.viewport.Height = chatHeight
 		}
-		m.textInput.Width = m.width - 4
+		m.textArea.SetWidth(m.width - 4)
+		m.rebuildMarkdownRenderer()
 		m.viewport.SetContent(m.renderMessages())
 		return m, nil
 
 	case Message:
 		m.messages = append(m.messages, msg)
-		m.threads[m.activeThread] = append(m.threads[m.activeThread], msg)
+		m.threads[m.activeThread] = m.messages
 		if m.ready {
 			m.viewport.SetContent(m.renderMessages())
 			m.viewport.GotoBottom()
 		}
 		return m, nil
 
+	case msgResponseChunk:
+		if n := len(m.messages); n > 0 {
+			m.messages[n-1].Content += msg.content
+		}
+		if m.ready {
+			m.viewport.SetContent(m.renderMessages())
+			m.viewport.GotoBottom()
+		}
+		return m, waitForStreamActivity(m.streamSub)
+
+	case msgResponseEnd:
+		return m, m.finishStream()
+
+	case msgResponseError:
+		if n := len(m.messages); n > 0 {
+			m.messages[n-1].Content = fmt.Sprintf("Error: %v", msg.err)
+		}
+		return m, m.finishStream()
+
+	case msgToolProgress:
+		m.messages = append(m.messages, Message{Role: "system", Content: msg.content, Time: time.Now()})
+		if m.ready {
+			m.viewport.SetContent(m.renderMessages())
+			m.viewport.GotoBottom()
+		}
+		return m, waitForStreamActivity(m.toolSub)
+
+	case msgToolResult:
+		m.toolSub = nil
+		var toolContent string
+		// Progress lines ("system" messages) land after the "tool"
+		// placeholder, so find it past them and move it to the end, so the
+		// resumed reply below is parented to the tool result.
+		idx := len(m.messages) - 1
+		for idx >= 0 && m.messages[idx].Role == "system" {
+			idx--
+		}
+		if idx >= 0 && m.messages[idx].Role == "tool" {
+			last := m.messages[idx]
+			m.messages = append(m.messages[:idx], m.messages[idx+1:]...)
+			if msg.err != nil {
+				last.Content = fmt.Sprintf("%s.%s failed: %v", msg.call.Module, msg.call.Operation, msg.err)
+			} else {
+				last.Content = msg.output
+			}
+			toolContent = last.Content
+			last.ParentID = 0
+			if idx >= 1 {
+				last.ParentID = m.messages[idx-1].ID
+			}
+			last.BranchID = m.nextBranchID(last.ParentID)
+			last.ID = m.persist(last)
+			m.messages = append(m.messages, last)
+			m.threads[m.activeThread] = m.messages
+		}
+
+		// Resume generation with the tool's output fed back as context,
+		// same as the initial send, so the neuron can produce a final
+		// answer instead of the conversation dead-ending on the result.
+		m.messages = append(m.messages, Message{Role: "assistant", Content: "", Time: time.Now()})
+		m.streaming = true
+		m.modelState = "thinking"
+		if m.ready {
+			m.viewport.SetContent(m.renderMessages())
+			m.viewport.GotoBottom()
+		}
+
+		neuronID := "elara"
+		if m.activeAgent != nil && m.activeAgent.Neuron != "" {
+			neuronID = m.activeAgent.Neuron
+		}
+		payload := fmt.Sprintf("<tool_result module=%q operation=%q>\n%s\n</tool_result>", msg.call.Module, msg.call.Operation, toolContent)
+		return m, m.startTurn(neuronID, payload)
+
+	case msgEditorFinished:
+		if msg.err == nil {
+			m.textArea.SetValue(msg.content)
+			m.textArea.CursorEnd()
+		}
+		return m, nil
+
 	case tickMsg:
 		return m, tickCmd()
 
 	case tea.KeyMsg:
-		// Global keys
+		// Global keys. Ctrl+Q/Ctrl+I are intercepted by the WindowManager
+		// before chat ever sees them, since they quit the app / open the
+		// help window rather than acting on chat state.
 		switch msg.String() {
-		case "ctrl+q":
-			return m, tea.Quit
-		case "ctrl+i":
-			m.showHelp = !m.showHelp
+		case "ctrl+c":
+			if m.streaming && m.stopSignal != nil {
+				close(m.stopSignal)
+				m.stopSignal = nil
+			}
 			return m, nil
-		}
-
-		// Help overlay consumes Esc
-		if m.showHelp {
-			if msg.String() == "esc" {
-				m.showHelp = false
+		case "ctrl+t":
+			m.showToolResults = !m.showToolResults
+			if m.ready {
+				m.viewport.SetContent(m.renderMessages())
 			}
 			return m, nil
+		case "ctrl+j":
+			if !m.focusMode && len(m.messages) == 0 {
+				return m, nil
+			}
+			m.focusMode = !m.focusMode
+			if m.focusMode {
+				m.cursorIndex = len(m.messages) - 1
+			}
+			if m.ready {
+				m.viewport.SetContent(m.renderMessages())
+			}
+			return m, nil
+		case "ctrl+e":
+			if !m.focusMode && !m.showPalette {
+				return m.openEditor()
+			}
+		}
+
+		// Message focus/branch navigation mode
+		if m.focusMode {
+			return m.updateFocusMode(msg)
 		}
 
 		// Command palette mode
@@ -229,72 +497,427 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updatePalette(msg)
 		}
 
-		// Chat input mode
+		// Chat input mode. Enter inserts a newline (forwarded to the
+		// textarea below) rather than sending; Ctrl+D/Alt+Enter send.
 		switch msg.String() {
 		case "/":
-			if m.textInput.Value() == "" {
+			if m.textArea.Value() == "" {
 				m.showPalette = true
 				m.paletteQuery = ""
 				m.paletteIndex = 0
 				m.filterPalette()
 				return m, nil
 			}
-		case "enter":
+		case "ctrl+d", "alt+enter":
 			return m.sendMessage()
 		}
 	}
 
-	// Forward to textInput
+	// Forward to textArea
 	var cmd tea.Cmd
-	m.textInput, cmd = m.textInput.Update(msg)
+	m.textArea, cmd = m.textArea.Update(msg)
 	cmds = append(cmds, cmd)
 
 	return m, tea.Batch(cmds...)
 }
 
 func (m Model) sendMessage() (tea.Model, tea.Cmd) {
-	content := strings.TrimSpace(m.textInput.Value())
+	content := strings.TrimSpace(m.textArea.Value())
 	if content == "" {
 		return m, nil
 	}
 
-	userMsg := Message{Role: "user", Content: content, Time: time.Now()}
+	// "/agent <name>" switches the active agent instead of sending a
+	// chat message, matching lmcli's -a/--agent design.
+	if name, ok := strings.CutPrefix(content, "/agent "); ok {
+		m.switchAgent(strings.TrimSpace(name))
+		m.textArea.SetValue("")
+		if m.ready {
+			m.viewport.SetContent(m.renderMessages())
+			m.viewport.GotoBottom()
+		}
+		return m, nil
+	}
+
+	// Editing a prior user message forks a new branch at that message's
+	// parent instead of appending to the end: drop it and everything
+	// after it from the active path, then resend as if typed fresh.
+	if m.editingID != 0 {
+		for i, msg := range m.messages {
+			if msg.ID == m.editingID {
+				m.messages = m.messages[:i]
+				break
+			}
+		}
+		m.editingID = 0
+	}
+
+	var parentID int64
+	if n := len(m.messages); n > 0 {
+		parentID = m.messages[n-1].ID
+	}
+	userMsg := Message{Role: "user", Content: content, Time: time.Now(), ParentID: parentID, BranchID: m.nextBranchID(parentID)}
+	userMsg.ID = m.persist(userMsg)
 	m.messages = append(m.messages, userMsg)
-	m.threads[m.activeThread] = append(m.threads[m.activeThread], userMsg)
-	m.textInput.SetValue("")
+	m.threads[m.activeThread] = m.messages
+	m.textArea.SetValue("")
 	m.modelState = "thinking"
 
+	// The assistant's reply starts empty and grows as msgResponseChunk
+	// events arrive; renderMessages draws a live cursor on it.
+	m.messages = append(m.messages, Message{Role: "assistant", Content: "", Time: time.Now()})
+	m.streaming = true
+
 	if m.ready {
 		m.viewport.SetContent(m.renderMessages())
 		m.viewport.GotoBottom()
 	}
 
-	// Dispatch to HiveMind
-	return m, func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-		defer cancel()
+	m.toolLoopDepth = 0
+
+	// An active agent prepends its system prompt and routes to its own
+	// neuron; otherwise fall back to the default chat neuron.
+	neuronID := "elara"
+	payload := content
+	if m.activeAgent != nil {
+		if m.activeAgent.Neuron != "" {
+			neuronID = m.activeAgent.Neuron
+		}
+		if m.activeAgent.SystemPrompt != "" {
+			payload = m.activeAgent.SystemPrompt + "\n\n" + content
+		}
+	}
+	if schema := m.toolSchema(); schema != "" {
+		payload = payload + "\n\n" + schema
+	}
+
+	return m, m.startTurn(neuronID, payload)
+}
+
+// msgEditorFinished carries the result of a $EDITOR session spawned by
+// openEditor: the tempfile's contents on success, or an error if the
+// editor exited non-zero or the file couldn't be read back.
+type msgEditorFinished struct {
+	content string
+	err     error
+}
+
+// openEditor suspends the TUI and spawns $EDITOR (falling back to vi) on
+// a tempfile prefilled with the current draft, mirroring lmcli's
+// long-prompt composition UX. On exit the tempfile's contents replace the
+// textarea's value — this doubles as the edit path for a prior message
+// selected via the branching feature's Ctrl+J/e, since both just need to
+// seed the textarea before sending.
+func (m Model) openEditor() (tea.Model, tea.Cmd) {
+	f, err := os.CreateTemp("", "cynapse-draft-*.md")
+	if err != nil {
+		return m, nil
+	}
+	path := f.Name()
+	_, werr := f.WriteString(m.textArea.Value())
+	f.Close()
+	if werr != nil {
+		os.Remove(path)
+		return m, nil
+	}
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	cmd := exec.Command(editor, path)
+	return m, tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return msgEditorFinished{err: err}
+		}
+		data, rerr := os.ReadFile(path)
+		if rerr != nil {
+			return msgEditorFinished{err: rerr}
+		}
+		return msgEditorFinished{content: strings.TrimRight(string(data), "\n")}
+	})
+}
+
+// startTurn fires off a generate task against neuronID and returns the
+// tea.Cmd that waits on its first event. It's shared by sendMessage (a
+// fresh user turn) and the tool-calling loop (resuming generation after a
+// <tool_call> block has been executed and fed back as a "tool" message).
+func (m *Model) startTurn(neuronID, payload string) tea.Cmd {
+	ctx, cancel := context.WithCancel(context.Background())
+	stop := make(chan struct{})
+	sub := make(chan tea.Msg)
+	m.stopSignal = stop
+	m.streamSub = sub
+
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
 
-		// For now, let's just call the neuron directly via engine
+	go func() {
+		defer cancel()
 		task := core.Task{
-			NeuronID:  "elara",
+			NeuronID:  neuronID,
 			Operation: "generate",
-			Payload:   []byte(content),
+			Payload:   []byte(payload),
 		}
 
-		var output string
-		res, err := m.engine.ExecuteTask(ctx, task)
+		chunks, err := m.engine.StreamTask(ctx, task)
 		if err != nil {
-			output = fmt.Sprintf("Error: %v", err)
-		} else {
-			output = res.Output
+			sub <- msgResponseError{err: err}
+			return
+		}
+		for res := range chunks {
+			if !res.Success {
+				sub <- msgResponseError{err: errors.New(res.Output)}
+				return
+			}
+			sub <- msgResponseChunk{content: res.Output}
+		}
+		sub <- msgResponseEnd{}
+	}()
+
+	return waitForStreamActivity(sub)
+}
+
+// toolAllowed reports whether the active agent may call moduleID: true if
+// there's no active agent (no restriction), or moduleID is in its Tools
+// allowlist. toolSchema uses this to build the advisory list shown to the
+// model, and runToolCall uses it again to enforce the same allowlist when
+// a <tool_call> is actually dispatched — the model's output isn't trusted
+// to have respected the advisory one.
+func (m *Model) toolAllowed(moduleID string) bool {
+	if m.activeAgent == nil {
+		return true
+	}
+	for _, id := range m.activeAgent.Tools {
+		if id == moduleID {
+			return true
+		}
+	}
+	return false
+}
+
+// toolSchema describes the IT modules the active agent may call as a JSON
+// block the neuron can read, so it knows what <tool_call> requests are
+// valid. Returns "" when there's no engine, no modules, or (for an active
+// agent with an empty allowlist) nothing the agent is permitted to call.
+func (m *Model) toolSchema() string {
+	if m.engine == nil || m.engine.ITMode() == nil {
+		return ""
+	}
+	mods := m.engine.ITMode().Registry().List()
+	var allow map[string]bool
+	if m.activeAgent != nil {
+		allow = make(map[string]bool, len(m.activeAgent.Tools))
+		for _, id := range m.activeAgent.Tools {
+			allow[id] = true
 		}
+	}
 
-		return Message{
-			Role:    "assistant",
-			Content: output,
-			Time:    time.Now(),
+	type toolDesc struct {
+		Module       string   `json:"module"`
+		Description  string   `json:"description"`
+		Capabilities []string `json:"capabilities"`
+	}
+	var tools []toolDesc
+	for _, mod := range mods {
+		if allow != nil && !allow[mod.ID] {
+			continue
 		}
+		tools = append(tools, toolDesc{Module: mod.ID, Description: mod.Description, Capabilities: mod.Capabilities})
+	}
+	if len(tools) == 0 {
+		return ""
+	}
+
+	schema, err := json.Marshal(tools)
+	if err != nil {
+		return ""
 	}
+	return "Available tools (invoke with <tool_call>{\"module\":\"...\",\"operation\":\"...\",\"params\":{...}}</tool_call>):\n" + string(schema)
+}
+
+// waitForStreamActivity blocks until the next event arrives on sub, then
+// hands it back into Update. Generic over its caller: startTurn uses it to
+// wait on an assistant reply's stream, runToolCall to wait on a tool
+// module's progress/result stream.
+func waitForStreamActivity(sub chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-sub
+	}
+}
+
+// persist saves msg to the active conversation, if a store is configured,
+// and returns the row ID assigned to it (0 if there's no store). Failures
+// are swallowed — persistence is best-effort and shouldn't stall or crash
+// the chat over a disk error. On success, the message is also recorded in
+// childrenOf so branch navigation and the "[branch N/M]" indicator see it
+// without a reload.
+func (m *Model) persist(msg Message) int64 {
+	if m.store == nil {
+		return 0
+	}
+	sm := store.Message{ParentID: msg.ParentID, BranchID: msg.BranchID, Role: msg.Role, Content: msg.Content, Time: msg.Time}
+	id, err := m.store.AppendMessage(m.activeThread, sm)
+	if err != nil {
+		return 0
+	}
+	sm.ID = id
+	if m.childrenOf == nil {
+		m.childrenOf = map[int64][]store.Message{}
+	}
+	m.childrenOf[msg.ParentID] = append(m.childrenOf[msg.ParentID], sm)
+	return id
+}
+
+// nextBranchID returns the ordinal the next child of parentID would get —
+// i.e. how many children it already has.
+func (m *Model) nextBranchID(parentID int64) int {
+	return len(m.childrenOf[parentID])
+}
+
+// openConversation switches activeThread to id and hydrates messages/
+// threads from the store, so switching conversations shows their full
+// history rather than starting blank. When a conversation has branches
+// (edited-and-resent messages), the most recently created branch at each
+// fork is shown by default.
+func (m *Model) openConversation(id string) {
+	m.activeThread = id
+	m.childrenOf = map[int64][]store.Message{}
+	m.focusMode = false
+	m.editingID = 0
+
+	var loaded []Message
+	if m.store != nil {
+		if stored, err := m.store.LoadMessages(id); err == nil {
+			for _, sm := range stored {
+				m.childrenOf[sm.ParentID] = append(m.childrenOf[sm.ParentID], sm)
+			}
+			loaded = m.latestPath(0)
+		}
+	}
+	if loaded == nil {
+		loaded = []Message{}
+	}
+	m.messages = loaded
+	m.threads[id] = loaded
+	if m.ready {
+		m.viewport.SetContent(m.renderMessages())
+		m.viewport.GotoBottom()
+	}
+}
+
+// latestPath walks the message DAG from parentID, always following the
+// most recently created child at each fork, and returns it as a flat
+// path of tui.Messages.
+func (m *Model) latestPath(parentID int64) []Message {
+	var path []Message
+	for {
+		kids := m.childrenOf[parentID]
+		if len(kids) == 0 {
+			return path
+		}
+		next := kids[len(kids)-1]
+		path = append(path, Message{
+			ID: next.ID, ParentID: next.ParentID, BranchID: next.BranchID,
+			Role: next.Role, Content: next.Content, Time: next.Time,
+		})
+		parentID = next.ID
+	}
+}
+
+// pathFrom rebuilds the tail of the active path starting at node,
+// following the most recently created child at every fork below it.
+func (m *Model) pathFrom(node store.Message) []Message {
+	head := Message{
+		ID: node.ID, ParentID: node.ParentID, BranchID: node.BranchID,
+		Role: node.Role, Content: node.Content, Time: node.Time,
+	}
+	return append([]Message{head}, m.latestPath(node.ID)...)
+}
+
+// finishStream commits the in-progress assistant message to the active
+// thread and clears the streaming state, whether the reply ended
+// normally, was cancelled, or errored. If the reply contains a
+// <tool_call> block and the per-turn loop budget isn't exhausted, it
+// strips the block, appends a placeholder "tool" message, and returns a
+// tea.Cmd that executes the call and resumes generation once it
+// completes.
+func (m *Model) finishStream() tea.Cmd {
+	m.streaming = false
+	m.modelState = "ready"
+	m.stopSignal = nil
+	m.streamSub = nil
+
+	var cmd tea.Cmd
+	if n := len(m.messages); n > 0 {
+		final := &m.messages[n-1]
+		final.ParentID = 0
+		if n >= 2 {
+			final.ParentID = m.messages[n-2].ID
+		}
+
+		if call, rest, ok := parseToolCall(final.Content); ok && m.toolLoopDepth < maxToolLoopDepth {
+			final.Content = rest
+			final.BranchID = m.nextBranchID(final.ParentID)
+			final.ID = m.persist(*final)
+			m.threads[m.activeThread] = m.messages
+
+			// The tool message starts as a placeholder and is filled in
+			// and persisted once runToolCall's result comes back, the
+			// same way the assistant reply above starts empty and is
+			// only persisted in finishStream.
+			m.messages = append(m.messages, Message{Role: "tool", Content: fmt.Sprintf("running %s.%s...", call.Module, call.Operation), Time: time.Now()})
+			m.toolLoopDepth++
+
+			cmd = m.runToolCall(call)
+		} else {
+			final.BranchID = m.nextBranchID(final.ParentID)
+			final.ID = m.persist(*final)
+			m.threads[m.activeThread] = m.messages
+		}
+	}
+	if m.ready {
+		m.viewport.SetContent(m.renderMessages())
+		m.viewport.GotoBottom()
+	}
+	return cmd
+}
+
+// runToolCall executes call against the engine's IT module executor in the
+// background, mirroring how startTurn's streaming goroutine reports back
+// through a channel: each line of module output arrives as a
+// msgToolProgress so a long-running diagnostic shows progress, and the
+// final outcome arrives as a msgToolResult once the module exits.
+func (m *Model) runToolCall(call toolCall) tea.Cmd {
+	sub := make(chan tea.Msg)
+	m.toolSub = sub
+
+	go func() {
+		if m.engine == nil || m.engine.ITMode() == nil {
+			sub <- msgToolResult{call: call, err: errors.New("no IT module executor configured")}
+			return
+		}
+		if !m.toolAllowed(call.Module) {
+			sub <- msgToolResult{call: call, err: fmt.Errorf("agent %q is not permitted to call module %q", m.activeAgent.Name, call.Module)}
+			return
+		}
+		res, err := m.engine.ITMode().ExecuteProgress(context.Background(), call.Module, call.Operation, call.Params, func(line string) {
+			sub <- msgToolProgress{call: call, content: line}
+		})
+		if err != nil {
+			sub <- msgToolResult{call: call, err: err}
+			return
+		}
+		sub <- msgToolResult{call: call, output: res.Output}
+	}()
+
+	return waitForStreamActivity(sub)
 }
 
 func (m *Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
@@ -339,6 +962,82 @@ func (m *Model) updatePalette(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// updateFocusMode handles key input while message navigation is active
+// (Ctrl+J): j/k move the cursor, h/l step between sibling branches at a
+// fork, "e" loads the cursor's user message into the input for editing,
+// and esc/Ctrl+J exit back to chat input.
+func (m *Model) updateFocusMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.focusMode = false
+		if m.ready {
+			m.viewport.SetContent(m.renderMessages())
+		}
+		return m, nil
+	case "j", "down":
+		if m.cursorIndex < len(m.messages)-1 {
+			m.cursorIndex++
+		}
+	case "k", "up":
+		if m.cursorIndex > 0 {
+			m.cursorIndex--
+		}
+	case "h":
+		m.switchSibling(-1)
+	case "l":
+		m.switchSibling(1)
+	case "e":
+		if m.cursorIndex >= 0 && m.cursorIndex < len(m.messages) {
+			cur := m.messages[m.cursorIndex]
+			if cur.Role == "user" {
+				m.textArea.SetValue(cur.Content)
+				m.textArea.CursorEnd()
+				m.editingID = cur.ID
+				m.focusMode = false
+			}
+		}
+	}
+	if m.ready {
+		m.viewport.SetContent(m.renderMessages())
+	}
+	return m, nil
+}
+
+// switchSibling moves the cursor message to the next (delta=1) or
+// previous (delta=-1) branch at its fork point, rebuilding everything
+// after it by following the most recently created child at each level
+// below the new branch.
+func (m *Model) switchSibling(delta int) {
+	if m.cursorIndex < 0 || m.cursorIndex >= len(m.messages) {
+		return
+	}
+	cur := m.messages[m.cursorIndex]
+	sibs := m.childrenOf[cur.ParentID]
+	if len(sibs) < 2 {
+		return
+	}
+	idx := -1
+	for i, s := range sibs {
+		if s.ID == cur.ID {
+			idx = i
+			break
+		}
+	}
+	if idx == -1 {
+		return
+	}
+	newIdx := idx + delta
+	if newIdx < 0 || newIdx >= len(sibs) {
+		return
+	}
+	suffix := m.pathFrom(sibs[newIdx])
+	m.messages = append(append([]Message{}, m.messages[:m.cursorIndex]...), suffix...)
+	m.threads[m.activeThread] = m.messages
+	if m.cursorIndex >= len(m.messages) {
+		m.cursorIndex = len(m.messages) - 1
+	}
+}
+
 func (m *Model) filterPalette() {
 	allCmds := append([]PaletteCommand{}, defaultCommands...)
 
@@ -354,6 +1053,17 @@ func (m *Model) filterPalette() {
 		}
 	}
 
+	// Add configured agents to palette
+	if m.agents != nil {
+		for _, a := range m.agents.List() {
+			allCmds = append(allCmds, PaletteCommand{
+				Name:        "agent " + a.Name,
+				Description: fmt.Sprintf("Switch to the %s agent (neuron: %s)", a.Name, a.Neuron),
+				Action:      "agent_" + a.Name,
+			})
+		}
+	}
+
 	if m.paletteQuery == "" {
 		m.paletteMatches = allCmds
 		return
@@ -369,6 +1079,28 @@ func (m *Model) filterPalette() {
 	m.paletteMatches = matches
 }
 
+// switchAgent sets the active agent by name, appending a system message
+// confirming the switch (or explaining why it couldn't happen). Used by
+// both the "agent <name>" palette action and the "/agent <name>" chat
+// command.
+func (m *Model) switchAgent(name string) {
+	if m.agents == nil {
+		m.messages = append(m.messages, Message{Role: "system", Content: "🤖 No agents configured (agents.yaml not found).", Time: time.Now()})
+		return
+	}
+	a, ok := m.agents.Get(name)
+	if !ok {
+		m.messages = append(m.messages, Message{Role: "system", Content: fmt.Sprintf("🤖 Unknown agent: %s", name), Time: time.Now()})
+		return
+	}
+	m.activeAgent = &a
+	m.messages = append(m.messages, Message{
+		Role:    "system",
+		Content: fmt.Sprintf("🤖 Switched to agent '%s' (neuron: %s, tools: %s)", a.Name, a.Neuron, strings.Join(a.Tools, ", ")),
+		Time:    time.Now(),
+	})
+}
+
 func (m *Model) executePaletteAction(action string) (tea.Model, tea.Cmd) {
 	switch action {
 	case "quit":
@@ -380,7 +1112,7 @@ func (m *Model) executePaletteAction(action string) (tea.Model, tea.Cmd) {
 			m.viewport.SetContent("")
 		}
 	case "help":
-		m.showHelp = true
+		return m, func() tea.Msg { return cmd.WinOpen{Target: "help"} }
 	case "health":
 		sysMsg := Message{Role: "system", Content: "🏥 Health check: Running diagnostics...", Time: time.Now()}
 		m.messages = append(m.messages, sysMsg)
@@ -389,21 +1121,10 @@ func (m *Model) executePaletteAction(action string) (tea.Model, tea.Cmd) {
 		m.messages = append(m.messages, sysMsg2)
 
 	case "neurons":
-		neurons := m.engine.ListNeurons()
-		sysMsg := Message{Role: "system", Content: fmt.Sprintf("🧠 Registered Neurons: %s", strings.Join(neurons, ", ")), Time: time.Now()}
-		m.messages = append(m.messages, sysMsg)
+		return m, func() tea.Msg { return cmd.WinOpen{Target: "neurons"} }
 
 	case "it_mode":
-		sysMsg := Message{Role: "system", Content: "🛠️ Entering IT Mode... Listing available modules:", Time: time.Now()}
-		m.messages = append(m.messages, sysMsg)
-		mods := m.engine.ITMode().Registry().List()
-		if len(mods) == 0 {
-			m.messages = append(m.messages, Message{Role: "system", Content: "  ❌ No IT modules found.", Time: time.Now()})
-		} else {
-			for _, mod := range mods {
-				m.messages = append(m.messages, Message{Role: "system", Content: fmt.Sprintf("  • %s (%s): %s", mod.Name, mod.ID, mod.Description), Time: time.Now()})
-			}
-		}
+		return m, func() tea.Msg { return cmd.WinOpen{Target: "itmodules"} }
 
 	case "threads":
 		var threadList []string
@@ -417,15 +1138,20 @@ func (m *Model) executePaletteAction(action string) (tea.Model, tea.Cmd) {
 		sysMsg := Message{Role: "system", Content: fmt.Sprintf("🧵 Active Threads: %s", strings.Join(threadList, ", ")), Time: time.Now()}
 		m.messages = append(m.messages, sysMsg)
 
-	case "agent_researcher":
-		sysMsg := Message{Role: "system", Content: "🤖 Researcher agent spawned.", Time: time.Now()}
-		m.messages = append(m.messages, sysMsg)
-
-	case "agent_coder":
-		sysMsg := Message{Role: "system", Content: "🤖 Coder agent spawned.", Time: time.Now()}
-		m.messages = append(m.messages, sysMsg)
+	case "conversations":
+		if m.store == nil {
+			sysMsg := Message{Role: "system", Content: "💬 No conversation store configured — chat history isn't persisted this session.", Time: time.Now()}
+			m.messages = append(m.messages, sysMsg)
+			break
+		}
+		return m, func() tea.Msg { return cmd.WinOpen{Target: "conversations"} }
 
 	default:
+		if strings.HasPrefix(action, "agent_") {
+			m.switchAgent(strings.TrimPrefix(action, "agent_"))
+			break
+		}
+
 		if strings.HasPrefix(action, "it_run_") {
 			moduleID := strings.TrimPrefix(action, "it_run_")
 			m.messages = append(m.messages, Message{Role: "system", Content: fmt.Sprintf("🛠️ Executing IT Module: %s...", moduleID), Time: time.Now()})
@@ -471,20 +1197,22 @@ func (m Model) View() string {
 		return "\n  Initializing Cynapse..."
 	}
 
+	agentLabel := ""
+	if m.activeAgent != nil {
+		agentLabel = fmt.Sprintf("  │  Agent: %s", m.activeAgent.Name)
+	}
 	header := headerStyle.Render(
-		fmt.Sprintf(" ⚡ CYNAPSE v4.0  │  Thread: %s  │  %s", m.activeThread, strings.ToUpper(m.modelState)),
+		fmt.Sprintf(" ⚡ CYNAPSE v4.0  │  Thread: %s%s  │  %s", m.activeThread, agentLabel, strings.ToUpper(m.modelState)),
 	)
 
 	statusBar := statusBarStyle.Render(
-		fmt.Sprintf(" Ctrl+Q quit │ / commands │ Ctrl+I help │ %d messages", len(m.messages)),
+		fmt.Sprintf(" Ctrl+Q quit │ / commands │ Ctrl+D send │ Ctrl+E editor │ Ctrl+I help │ Ctrl+C stop reply │ %d messages", len(m.messages)),
 	)
 
-	input := m.textInput.View()
+	input := m.textArea.View()
 
 	var content string
-	if m.showHelp {
-		content = m.renderHelp()
-	} else if m.showPalette {
+	if m.showPalette {
 		content = m.viewport.View() + "\n" + m.renderPalette()
 	} else {
 		content = m.viewport.View()
@@ -502,21 +1230,112 @@ func (m Model) View() string {
 
 func (m Model) renderMessages() string {
 	var lines []string
-	for _, msg := range m.messages {
+	for i, msg := range m.messages {
 		ts := msg.Time.Format("15:04")
+		live := m.streaming && msg.Role == "assistant" && i == len(m.messages)-1
+		branch := m.branchIndicator(msg)
+
+		var line string
 		switch msg.Role {
 		case "user":
-			lines = append(lines, userMsgStyle.Render(fmt.Sprintf("[%s] You: %s", ts, msg.Content)))
+			content := msg.Content
+			if branch != "" {
+				content += " " + branch
+			}
+			line = userMsgStyle.Render(fmt.Sprintf("[%s] You: %s", ts, content))
 		case "assistant":
-			lines = append(lines, assistantMsgStyle.Render(fmt.Sprintf("[%s] Cynapse: %s", ts, msg.Content)))
+			body := msg.Content
+			if live {
+				body += "▋"
+			}
+			header := assistantMsgStyle.Render(fmt.Sprintf("[%s] Cynapse:", ts))
+			line = header + "\n" + m.renderMarkdown(body, live)
+			if branch != "" {
+				line += "\n" + systemMsgStyle.Render("  "+branch)
+			}
 		case "system":
-			lines = append(lines, systemMsgStyle.Render(fmt.Sprintf("[%s] %s", ts, msg.Content)))
+			header := systemMsgStyle.Render(fmt.Sprintf("[%s] ", ts))
+			line = header + m.renderMarkdown(msg.Content, false)
+		case "tool":
+			// Collapsed by default (Ctrl+T expands), mirroring lmcli's
+			// showToolResults toggle, so a verbose module output doesn't
+			// bury the conversation.
+			if m.showToolResults {
+				line = toolMsgStyle.Render(fmt.Sprintf("[%s] tool: %s", ts, msg.Content))
+			} else {
+				line = toolMsgStyle.Render(fmt.Sprintf("[%s] tool result (ctrl+t to expand)", ts))
+			}
 		}
+		if m.focusMode && i == m.cursorIndex {
+			line = focusCursorStyle.Render("▶ ") + line
+		}
+		lines = append(lines, line)
 		lines = append(lines, "") // spacing
 	}
 	return strings.Join(lines, "\n")
 }
 
+// rebuildMarkdownRenderer (re)creates mdRenderer for the current viewport
+// width and drops the render cache, since every cached entry is keyed to
+// the width it was wrapped at. Called whenever the terminal resizes.
+func (m *Model) rebuildMarkdownRenderer() {
+	width := m.viewport.Width
+	if width <= 0 {
+		width = 80
+	}
+	if r, err := glamour.NewTermRenderer(
+		glamour.WithStandardStyle("dark"),
+		glamour.WithWordWrap(width),
+	); err == nil {
+		m.mdRenderer = r
+	}
+	m.messageCache = map[messageCacheKey]string{}
+}
+
+// renderMarkdown renders content (assistant/system message bodies) as
+// markdown via glamour — which uses Chroma for fenced code blocks, with
+// language detection — re-wrapping the result with reflow/wordwrap so
+// long lines don't overflow the viewport. Results are cached by
+// (content, width); the in-progress streaming message (live) is never
+// cached since its content changes on every chunk.
+func (m Model) renderMarkdown(content string, live bool) string {
+	width := m.viewport.Width
+	if width <= 0 || m.mdRenderer == nil {
+		return content
+	}
+	key := messageCacheKey{content: content, width: width}
+	if !live {
+		if cached, ok := m.messageCache[key]; ok {
+			return cached
+		}
+	}
+	rendered, err := m.mdRenderer.Render(content)
+	if err != nil {
+		return content
+	}
+	rendered = strings.TrimRight(rendered, "\n")
+	rendered = wordwrap.String(rendered, width)
+	if !live {
+		m.messageCache[key] = rendered
+	}
+	return rendered
+}
+
+// branchIndicator returns "[branch N/M]" when msg has sibling branches
+// (an earlier edit forked at its parent), or "" otherwise.
+func (m Model) branchIndicator(msg Message) string {
+	sibs := m.childrenOf[msg.ParentID]
+	if len(sibs) < 2 {
+		return ""
+	}
+	for i, s := range sibs {
+		if s.ID == msg.ID {
+			return fmt.Sprintf("[branch %d/%d]", i+1, len(sibs))
+		}
+	}
+	return ""
+}
+
 func (m Model) renderPalette() string {
 	var items []string
 	title := paletteTitleStyle.Render("⚡ Command Palette")
@@ -535,29 +1354,3 @@ func (m Model) renderPalette() string {
 	return paletteStyle.Render(strings.Join(items, "\n"))
 }
 
-func (m Model) renderHelp() string {
-	help := `
-  ⌨️  KEYBOARD SHORTCUTS
-  ─────────────────────
-
-  /              Open command palette
-  Ctrl+Q         Quit
-  Ctrl+I         Toggle this help
-  Enter          Send message
-  Esc            Close palette / help
-
-  COMMANDS (via palette)
-  ─────────────────────
-  quit           Exit Cynapse
-  clear          Clear chat
-  health         System diagnostics
-  neurons        List available neurons
-  agent <role>   Spawn sub-agent
-  it-mode        Self-repair mode
-  threads        Show active threads
-`
-	return lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#ABB2BF")).
-		Padding(1, 2).
-		Render(help)
-}
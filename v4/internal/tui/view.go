@@ -0,0 +1,11 @@
+package tui
+
+import tea "github.com/charmbracelet/bubbletea"
+
+// View is a window the WindowManager can host. It's just tea.Model plus an
+// ID so the manager can tell its windows apart by it — any
+// existing Bubble Tea model becomes a View for free by adding ID().
+type View interface {
+	tea.Model
+	ID() string
+}
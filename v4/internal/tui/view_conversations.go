@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Alartist40/cynapse/internal/store"
+	"github.com/Alartist40/cynapse/internal/tui/cmd"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// conversationsView lists saved conversations and lets the user switch,
+// create, or delete them. Switching can't be done locally — only the
+// WindowManager holds the chat view — so picking a conversation emits a
+// cmd.ConversationSelected for the manager to act on instead of mutating
+// anything here.
+type conversationsView struct {
+	store        *store.Store
+	activeThread string // highlighted at Init; delete checks it to spot removal of the open conversation
+
+	list            []store.Conversation
+	index           int
+	confirmDeleteID string
+}
+
+func newConversationsView(st *store.Store, activeThread string) *conversationsView {
+	return &conversationsView{store: st, activeThread: activeThread}
+}
+
+func (v *conversationsView) ID() string { return "conversations" }
+
+func (v *conversationsView) Init() tea.Cmd {
+	v.confirmDeleteID = ""
+	if v.store == nil {
+		v.list = nil
+		return nil
+	}
+	convs, err := v.store.ListConversations()
+	if err != nil {
+		v.list = nil
+		return nil
+	}
+	v.list = convs
+	v.index = 0
+	for i, c := range convs {
+		if c.ID == v.activeThread {
+			v.index = i
+			break
+		}
+	}
+	return nil
+}
+
+func (v *conversationsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return v, nil
+	}
+	switch key.String() {
+	case "up":
+		if v.index > 0 {
+			v.index--
+		}
+		v.confirmDeleteID = ""
+	case "down":
+		if v.index < len(v.list)-1 {
+			v.index++
+		}
+		v.confirmDeleteID = ""
+	case "enter":
+		if v.index < len(v.list) {
+			id := v.list[v.index].ID
+			return v, func() tea.Msg { return cmd.ConversationSelected{ID: id} }
+		}
+	case "n":
+		if v.store != nil {
+			if conv, err := v.store.CreateConversation("New conversation"); err == nil {
+				return v, func() tea.Msg { return cmd.ConversationSelected{ID: conv.ID} }
+			}
+		}
+	case "d":
+		if v.index >= len(v.list) {
+			return v, nil
+		}
+		target := v.list[v.index].ID
+		if v.confirmDeleteID != target {
+			v.confirmDeleteID = target
+			return v, nil
+		}
+		v.confirmDeleteID = ""
+		if v.store == nil {
+			return v, nil
+		}
+		v.store.DeleteConversation(target)
+		deletedCmd := func() tea.Msg { return cmd.ConversationDeleted{ID: target} }
+		if convs, err := v.store.ListConversations(); err == nil {
+			v.list = convs
+			if v.index >= len(v.list) {
+				v.index = len(v.list) - 1
+			}
+		}
+		if target != v.activeThread {
+			return v, deletedCmd
+		}
+		// The active conversation was just deleted — hand the manager
+		// whatever's left to switch to, creating a fresh one if needed.
+		if len(v.list) > 0 {
+			id := v.list[0].ID
+			return v, tea.Batch(deletedCmd, func() tea.Msg { return cmd.ConversationSelected{ID: id} })
+		}
+		if conv, err := v.store.CreateConversation("New conversation"); err == nil {
+			return v, tea.Batch(deletedCmd, func() tea.Msg { return cmd.ConversationSelected{ID: conv.ID} })
+		}
+		return v, deletedCmd
+	}
+	return v, nil
+}
+
+func (v *conversationsView) View() string {
+	var b strings.Builder
+	b.WriteString("💬 Conversations (n: new, d: delete twice to confirm, enter: switch, esc: close)\n\n")
+	if len(v.list) == 0 {
+		b.WriteString("  (none yet)\n")
+	}
+	for i, conv := range v.list {
+		marker := "  "
+		if i == v.index {
+			marker = "▶ "
+		}
+		line := fmt.Sprintf("%s%s — %s", marker, conv.Title, conv.UpdatedAt.Format("2006-01-02 15:04"))
+		if conv.ID == v.confirmDeleteID {
+			line += "  (press d again to delete)"
+		}
+		b.WriteString(line + "\n")
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
@@ -0,0 +1,58 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// helpView is the keyboard-shortcut reference, opened with Ctrl+I. It's
+// static, so Update only needs to satisfy the View interface.
+type helpView struct{}
+
+func newHelpView() *helpView { return &helpView{} }
+
+func (v *helpView) ID() string { return "help" }
+
+func (v *helpView) Init() tea.Cmd { return nil }
+
+func (v *helpView) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return v, nil }
+
+func (v *helpView) View() string {
+	help := `
+  ⌨️  KEYBOARD SHORTCUTS
+  ─────────────────────
+
+  /              Open command palette
+  Ctrl+Q         Quit
+  Ctrl+I         Toggle this help window
+  Ctrl+C         Stop an in-progress reply
+  Ctrl+J         Toggle message navigation mode
+  Ctrl+T         Expand/collapse tool call results
+  Ctrl+E         Compose in $EDITOR
+  Enter          Insert newline
+  Ctrl+D / Alt+Enter  Send message
+  Esc            Close this window
+
+  MESSAGE NAVIGATION (after Ctrl+J)
+  ─────────────────────
+  j / k          Move cursor down / up
+  h / l          Switch to previous / next branch
+  e              Edit cursor's message and resend (forks a branch)
+  Esc            Exit navigation mode
+
+  COMMANDS (via palette)
+  ─────────────────────
+  quit           Exit Cynapse
+  clear          Clear chat
+  health         System diagnostics
+  neurons        List available neurons
+  agent <name>   Switch to a configured agent (or type /agent <name>)
+  it-mode        List IT support modules
+  threads        Show active threads
+  conversations  List, switch, or delete saved conversations
+`
+	return lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#ABB2BF")).
+		Padding(1, 2).
+		Render(help)
+}
@@ -0,0 +1,45 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Alartist40/cynapse/internal/hivemind"
+	"github.com/Alartist40/cynapse/internal/techsupport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// itmodulesView lists the IT Support modules available through the
+// engine's Executor — the former "it-mode" palette action, now a window.
+type itmodulesView struct {
+	engine  *hivemind.Engine
+	modules []techsupport.ModuleInfo
+}
+
+func newITModulesView(engine *hivemind.Engine) *itmodulesView {
+	return &itmodulesView{engine: engine}
+}
+
+func (v *itmodulesView) ID() string { return "itmodules" }
+
+func (v *itmodulesView) Init() tea.Cmd {
+	if v.engine != nil && v.engine.ITMode() != nil {
+		v.modules = v.engine.ITMode().Registry().List()
+	}
+	return nil
+}
+
+func (v *itmodulesView) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return v, nil }
+
+func (v *itmodulesView) View() string {
+	var b strings.Builder
+	b.WriteString("🛠️  IT Support Modules (esc: close)\n\n")
+	if len(v.modules) == 0 {
+		b.WriteString("  (none found)\n")
+	}
+	for _, mod := range v.modules {
+		fmt.Fprintf(&b, "  • %s (%s): %s\n", mod.Name, mod.ID, mod.Description)
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
@@ -0,0 +1,43 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Alartist40/cynapse/internal/hivemind"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// neuronsView lists the neurons registered with the HiveMind engine.
+type neuronsView struct {
+	engine *hivemind.Engine
+	names  []string
+}
+
+func newNeuronsView(engine *hivemind.Engine) *neuronsView {
+	return &neuronsView{engine: engine}
+}
+
+func (v *neuronsView) ID() string { return "neurons" }
+
+func (v *neuronsView) Init() tea.Cmd {
+	if v.engine != nil {
+		v.names = v.engine.ListNeurons()
+	}
+	return nil
+}
+
+func (v *neuronsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return v, nil }
+
+func (v *neuronsView) View() string {
+	var b strings.Builder
+	b.WriteString("🧠 Registered Neurons (esc: close)\n\n")
+	if len(v.names) == 0 {
+		b.WriteString("  (none registered)\n")
+	}
+	for _, n := range v.names {
+		fmt.Fprintf(&b, "  • %s\n", n)
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(b.String())
+}
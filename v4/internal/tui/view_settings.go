@@ -0,0 +1,24 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// settingsView is a placeholder window for future configuration toggles
+// (there's nothing user-configurable yet beyond agents.yaml/the palette).
+type settingsView struct{}
+
+func newSettingsView() *settingsView { return &settingsView{} }
+
+func (v *settingsView) ID() string { return "settings" }
+
+func (v *settingsView) Init() tea.Cmd { return nil }
+
+func (v *settingsView) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return v, nil }
+
+func (v *settingsView) View() string {
+	return lipgloss.NewStyle().
+		Padding(1, 2).
+		Render("⚙️  Settings (esc: close)\n\nNothing here yet — agents.yaml is the only configuration surface so far.")
+}
@@ -0,0 +1,166 @@
+package tui
+
+import (
+	"github.com/Alartist40/cynapse/internal/agents"
+	"github.com/Alartist40/cynapse/internal/hivemind"
+	"github.com/Alartist40/cynapse/internal/store"
+	"github.com/Alartist40/cynapse/internal/tui/cmd"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// WindowManager is the Bubble Tea program's top-level model. It always
+// hosts the chat view and, on top of it, a stack of other Views pushed by
+// cmd.WinOpen messages — conversations, neurons, itmodules, help, or
+// settings — of which only the topmost is active, so new windows can be added without chat's Update growing
+// another overlay flag, per neonmodem's wm command-bus design.
+type WindowManager struct {
+	engine *hivemind.Engine
+	store  *store.Store
+	agents *agents.Registry
+
+	chat    Model
+	windows []View // stack of non-chat windows; top is active. Empty means chat is active.
+
+	width, height int
+}
+
+// NewWindowManager builds the manager with the chat view ready to go;
+// every other window is created afresh each time it's opened.
+func NewWindowManager(engine *hivemind.Engine, st *store.Store, agentsReg *agents.Registry) WindowManager {
+	return WindowManager{
+		engine: engine,
+		store:  st,
+		agents: agentsReg,
+		chat:   New(engine, st, agentsReg),
+	}
+}
+
+func (m WindowManager) Init() tea.Cmd {
+	return m.chat.Init()
+}
+
+// active returns the topmost non-chat window, or nil if chat is active.
+func (m WindowManager) active() View {
+	if len(m.windows) == 0 {
+		return nil
+	}
+	return m.windows[len(m.windows)-1]
+}
+
+// newBuiltinView constructs one of the shipped window types by ID. Returns
+// nil for an unknown target, so a typo'd cmd.WinOpen is a no-op rather
+// than a crash.
+func newBuiltinView(id string, engine *hivemind.Engine, st *store.Store, activeThread string) View {
+	switch id {
+	case "conversations":
+		return newConversationsView(st, activeThread)
+	case "neurons":
+		return newNeuronsView(engine)
+	case "itmodules":
+		return newITModulesView(engine)
+	case "help":
+		return newHelpView()
+	case "settings":
+		return newSettingsView()
+	default:
+		return nil
+	}
+}
+
+// openWindow pushes the named window onto the stack and initializes it,
+// sizing it immediately so it doesn't render at zero width before the
+// next resize event.
+func (m *WindowManager) openWindow(id string) tea.Cmd {
+	if id == "chat" {
+		m.windows = nil
+		return nil
+	}
+	v := newBuiltinView(id, m.engine, m.store, m.chat.activeThread)
+	if v == nil {
+		return nil
+	}
+	initCmd := v.Init()
+	if m.width > 0 {
+		sized, _ := v.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		v = sized.(View)
+	}
+	m.windows = append(m.windows, v)
+	return initCmd
+}
+
+// closeWindow pops the active window, if any, back to whatever was below
+// it (ultimately chat).
+func (m *WindowManager) closeWindow() {
+	if len(m.windows) > 0 {
+		m.windows = m.windows[:len(m.windows)-1]
+	}
+}
+
+func (m WindowManager) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		newChat, c := m.chat.Update(msg)
+		m.chat = newChat.(Model)
+		cmds := []tea.Cmd{c}
+		for i, v := range m.windows {
+			newV, vc := v.Update(msg)
+			m.windows[i] = newV.(View)
+			cmds = append(cmds, vc)
+		}
+		return m, tea.Batch(cmds...)
+
+	case cmd.WinOpen:
+		return m, m.openWindow(msg.Target)
+
+	case cmd.WMCloseWin:
+		m.closeWindow()
+		return m, nil
+
+	case cmd.ConversationSelected:
+		m.chat.openConversation(msg.ID)
+		if m.chat.ready {
+			m.chat.viewport.SetContent(m.chat.renderMessages())
+			m.chat.viewport.GotoBottom()
+		}
+		m.closeWindow()
+		return m, nil
+
+	case cmd.ConversationDeleted:
+		delete(m.chat.threads, msg.ID)
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "ctrl+q":
+			return m, tea.Quit
+		case "ctrl+i":
+			if v := m.active(); v != nil && v.ID() == "help" {
+				m.closeWindow()
+				return m, nil
+			}
+			return m, m.openWindow("help")
+		case "esc":
+			if m.active() != nil {
+				m.closeWindow()
+				return m, nil
+			}
+		}
+	}
+
+	if v := m.active(); v != nil {
+		newV, c := v.Update(msg)
+		m.windows[len(m.windows)-1] = newV.(View)
+		return m, c
+	}
+	newChat, c := m.chat.Update(msg)
+	m.chat = newChat.(Model)
+	return m, c
+}
+
+func (m WindowManager) View() string {
+	if v := m.active(); v != nil {
+		return v.View()
+	}
+	return m.chat.View()
+}